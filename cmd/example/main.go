@@ -29,7 +29,7 @@ func main() {
 	_ = data2.AddChild(&TestData{
 		Data: "B",
 	})
-	tree := view.NewTypeBaseTree(data, data2)
+	tree := view.NewTypeBaseTree(view.WithTypeBaseRoots(data, data2))
 	var i int
 	tree.OnDoubleTapped = func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) {
 		log.Printf("Adding child to parent '%s'\n", id)
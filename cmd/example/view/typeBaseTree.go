@@ -3,58 +3,139 @@
 package view
 
 import (
-	"log"
-
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/widget"
 	"github.com/drognisep/fynehelpers/generation"
 )
 
-var _ fyne.CanvasObject = (*TypeBaseTree)(nil)
+var _ fyne.Widget = (*TypeBaseTree)(nil)
 
-// TypeBaseTree is a widget.Tree implementation that manages IDs through generation.TreeModelRegistry.
-// This is designed to be the gatekeeper for all widget and model mutations.
+// TypeBaseTree is a generation.Tree with the features it was generated with, and handlers that receive
+// models as generation.TreeModel. The embedded Tree manages IDs through its registry, and is the gatekeeper for all widget and
+// model mutations.
 type TypeBaseTree struct {
-	widget.Tree
-	*generation.TreeModelRegistry
-	OnTapped          func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnTapped is called by the typeBaseNode that receives an event from Fyne.
-
-	OnDoubleTapped    func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnDoubleTapped is called by the typeBaseNode that receives an event from Fyne.
+	*generation.Tree
+	OnTapped          func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnTapped is called when a node is tapped, after it's selected.
+	OnDoubleTapped    func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnDoubleTapped is called when a node is double tapped.
+	OnTappedSecondary func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnTappedSecondary is called when a node is secondary tapped.
+	options           []generation.TreeOption                                                        // options configure the embedded Tree as it's created.
+}
 
-	OnTappedSecondary func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnTappedSecondary is called by the typeBaseNode that receives an event from Fyne.
+// NewTypeBaseTree creates a tree configured by options, and adds the roots given with
+// WithTypeBaseRoots to its registry. Roots that can't be added are passed to the error handler.
+func NewTypeBaseTree(options ...TypeBaseTreeOption) *TypeBaseTree {
+	tree := newTypeBaseTree(options)
+	tree.setTree(generation.NewTree(tree.options...))
+	return tree
+}
 
+// NewTypeBaseTreeE creates a tree like NewTypeBaseTree, but returns the first error from adding
+// roots instead of passing it to the error handler.
+func NewTypeBaseTreeE(options ...TypeBaseTreeOption) (*TypeBaseTree, error) {
+	tree := newTypeBaseTree(options)
+	inner, err := generation.NewTreeE(tree.options...)
+	if err != nil {
+		return nil, err
+	}
+	tree.setTree(inner)
+	return tree, nil
 }
 
-// NewTypeBaseTree initializes the tree and adds all modelRoots to the registry.
-func NewTypeBaseTree(modelRoots ...generation.TreeModel) *TypeBaseTree {
-	tree := &TypeBaseTree{
-		TreeModelRegistry: generation.NewTreeModelRegistry(),
+func newTypeBaseTree(options []TypeBaseTreeOption) *TypeBaseTree {
+	tree := &TypeBaseTree{}
+	// Handlers are passed to the embedded Tree as they're called, so they may be set at any time.
+	tree.options = []generation.TreeOption{
+		generation.WithExtendingWidget(tree),
+		generation.WithOnTapped(func(id widget.TreeNodeID, _ generation.TreeModel, event *fyne.PointEvent) {
+			tree.Tapped(id, event)
+		}),
+		generation.WithOnDoubleTapped(func(id widget.TreeNodeID, _ generation.TreeModel, event *fyne.PointEvent) {
+			tree.DoubleTapped(id, event)
+		}),
+		generation.WithOnTappedSecondary(func(id widget.TreeNodeID, _ generation.TreeModel, event *fyne.PointEvent) {
+			tree.TappedSecondary(id, event)
+		}),
 	}
-	tree.Tree = widget.Tree{
-		ChildUIDs: tree.Children,
-		CreateNode: func(bool) (o fyne.CanvasObject) {
-			return newTypeBaseNode(tree)
-		},
-		IsBranch: tree.HasChildren,
-		UpdateNode: func(id widget.TreeNodeID, isBranch bool, node fyne.CanvasObject) {
-			treeModel, ok := node.(*typeBaseNode)
-			if !ok {
-				return
-			}
-			modelNode := tree.Node(id)
-			if modelNode == nil {
-				return
-			}
-			treeModel.update(id, modelNode)
-		},
+	for _, option := range options {
+		option(tree)
 	}
-	for _, root := range modelRoots {
-		if _, err := tree.AddChild("", root); err != nil {
-			log.Printf("Error adding model root: %v\n%v\n", err, root)
+	return tree
+}
+
+// setTree embeds the Tree created from the options.
+func (t *TypeBaseTree) setTree(tree *generation.Tree) {
+	t.Tree = tree
+	t.options = nil
+}
+
+// TypeBaseTreeOption configures a TypeBaseTree as it's created.
+type TypeBaseTreeOption func(tree *TypeBaseTree)
+
+// WithTypeBaseRoots adds roots to the tree's registry once every other option has been applied.
+func WithTypeBaseRoots(roots ...generation.TreeModel) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		for _, root := range roots {
+			tree.options = append(tree.options, generation.WithRoots(root))
 		}
 	}
-	tree.ExtendBaseWidget(tree)
-	return tree
+}
+
+// WithTypeBaseErrorHandler handles errors the tree can't return, like failing to add a root or drop a node.
+// They're logged if handler is nil.
+func WithTypeBaseErrorHandler(handler func(err error)) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.options = append(tree.options, generation.WithErrorHandler(handler))
+	}
+}
+
+// WithTypeBaseRegistry displays the nodes in an existing registry, instead of creating an empty one.
+func WithTypeBaseRegistry(registry *generation.TreeModelRegistry) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.options = append(tree.options, generation.WithRegistry(registry))
+	}
+}
+
+// WithTypeBaseIDStrategy sets the strategy the tree's registry uses to create node IDs, see
+// generation.TreeModelRegistry.SetIDStrategy.
+func WithTypeBaseIDStrategy(strategy generation.IDStrategy) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.options = append(tree.options, generation.WithIDStrategy(strategy))
+	}
+}
+
+// WithTypeBaseNodeContent displays models with content created by factory, instead of an icon and label.
+func WithTypeBaseNodeContent(factory generation.NodeContentFactory) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.options = append(tree.options, generation.WithNodeContent(factory))
+	}
+}
+
+// WithTypeBaseOnTapped sets OnTapped.
+func WithTypeBaseOnTapped(handler func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent)) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.OnTapped = handler
+	}
+}
+
+// WithTypeBaseOnDoubleTapped sets OnDoubleTapped.
+func WithTypeBaseOnDoubleTapped(handler func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent)) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.OnDoubleTapped = handler
+	}
+}
+
+// WithTypeBaseOnTappedSecondary sets OnTappedSecondary.
+func WithTypeBaseOnTappedSecondary(handler func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent)) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.OnTappedSecondary = handler
+	}
+}
+
+// WithTypeBaseOnSelected sets OnSelected and OnUnselected. Either of them may be nil.
+func WithTypeBaseOnSelected(selected, unselected func(id widget.TreeNodeID)) TypeBaseTreeOption {
+	return func(tree *TypeBaseTree) {
+		tree.options = append(tree.options, generation.WithOnSelected(selected, unselected))
+	}
 }
 
 func (t *TypeBaseTree) Tapped(id widget.TreeNodeID, event *fyne.PointEvent) {
@@ -63,28 +144,14 @@ func (t *TypeBaseTree) Tapped(id widget.TreeNodeID, event *fyne.PointEvent) {
 	}
 }
 
-
 func (t *TypeBaseTree) DoubleTapped(id widget.TreeNodeID, event *fyne.PointEvent) {
 	if t.OnDoubleTapped != nil {
 		t.OnDoubleTapped(id, t.Node(id), event)
 	}
 }
 
-
 func (t *TypeBaseTree) TappedSecondary(id widget.TreeNodeID, event *fyne.PointEvent) {
 	if t.OnTappedSecondary != nil {
 		t.OnTappedSecondary(id, t.Node(id), event)
 	}
 }
-
-func (t *TypeBaseTree) AddChild(parentID widget.TreeNodeID, data generation.TreeModel) (widget.TreeNodeID, error) {
-	defer t.Refresh()
-	return t.TreeModelRegistry.AddChild(parentID, data)
-}
-
-func (t *TypeBaseTree) RemoveChild(dataID widget.TreeNodeID) {
-	defer t.Refresh()
-	t.TreeModelRegistry.RemoveChild(dataID)
-}
-
-
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"go/format"
 	"log"
 	"os"
 	"strings"
@@ -25,12 +26,34 @@ const (
 	eventTappedFlag       = "event-tapped"
 	eventDoubleTappedFlag = "event-double-tapped"
 	eventSecondTappedFlag = "event-secondary-tapped"
+	eventHoverFlag        = "event-hover"
+	eventMouseFlag        = "event-mouse"
+	tooltipsFlag          = "tooltips"
+	checkableFlag         = "checkable"
+	multiSelectFlag       = "multi-select"
+	draggableFlag         = "draggable"
+	editableFlag          = "editable"
+	contextMenuFlag       = "context-menu"
+	keyboardFlag          = "keyboard"
+	modelTypeFlag         = "model-type"
+	modelImportFlag       = "model-import"
 )
 
 var (
 	eventTappedVal       bool
 	eventDoubleTappedVal bool
 	eventSecondTappedVal bool
+	eventHoverVal        bool
+	eventMouseVal        bool
+	tooltipsVal          bool
+	checkableVal         bool
+	multiSelectVal       bool
+	draggableVal         bool
+	editableVal          bool
+	contextMenuVal       bool
+	keyboardVal          bool
+	modelTypeVal         string
+	modelImportVal       string
 )
 
 func init() {
@@ -39,17 +62,27 @@ func init() {
 	treeCmd.Flags().BoolVar(&eventTappedVal, eventTappedFlag, false, "Indicates that the tree node should be tappable")
 	treeCmd.Flags().BoolVar(&eventDoubleTappedVal, eventDoubleTappedFlag, false, "Indicates that the tree node should be double-tappable")
 	treeCmd.Flags().BoolVar(&eventSecondTappedVal, eventSecondTappedFlag, false, "Indicates that the tree node should be secondary-tappable")
+	treeCmd.Flags().BoolVar(&eventHoverVal, eventHoverFlag, false, "Indicates that the tree node should report the pointer entering, moving over and leaving it")
+	treeCmd.Flags().BoolVar(&eventMouseVal, eventMouseFlag, false, "Indicates that the tree node should report mouse button presses and releases")
+	treeCmd.Flags().BoolVar(&tooltipsVal, tooltipsFlag, false, "Shows the description of models implementing generation.DescribedTreeModel when hovering over a node")
+	treeCmd.Flags().BoolVar(&checkableVal, checkableFlag, false, "Adds a tri-state checkbox to each node, with check state propagated to parents and children")
+	treeCmd.Flags().BoolVar(&multiSelectVal, multiSelectFlag, false, "Allows selecting multiple nodes with control/shift-click, replacing the widget.Tree selection")
+	treeCmd.Flags().BoolVar(&draggableVal, draggableFlag, false, "Allows reordering and reparenting nodes by dragging them")
+	treeCmd.Flags().BoolVar(&editableVal, editableFlag, false, "Allows renaming nodes whose models implement generation.RenamableTreeModel, with double-click or F2")
+	treeCmd.Flags().BoolVar(&contextMenuVal, contextMenuFlag, false, "Shows a context menu from MenuFor and generation.ContextMenuTreeModel when a node is secondary tapped")
+	treeCmd.Flags().BoolVar(&keyboardVal, keyboardFlag, false, "Makes the tree focusable, with arrow keys, Home/End, type-ahead, Enter and Delete acting on the current node")
+	treeCmd.Flags().StringVar(&modelTypeVal, modelTypeFlag, "", "Generates a tree backed by generation.TypedRegistry for the given model type, e.g. '*Folder'")
+	treeCmd.Flags().StringVar(&modelImportVal, modelImportFlag, "", "Import path of the package declaring the model type, if it's not the generated package")
 }
 
 func generateTree(*cobra.Command, []string) {
-	normalization.TrimSpace(&packageVal, &fileVal, &typeVal)
+	normalization.TrimSpace(&packageVal, &fileVal, &typeVal, &modelTypeVal, &modelImportVal)
 	cobra.CheckErr(validation.NoneBlank(map[string]string{
 		packageFlag: packageVal,
 		fileFlag:    fileVal,
 		typeFlag:    typeVal,
 	}))
 	fileVal = strings.TrimSuffix(fileVal, ".go")
-	var nodeBuf bytes.Buffer
 	var treeBuf bytes.Buffer
 	params := &TreeGenParams{
 		Package:         packageVal,
@@ -57,16 +90,23 @@ func generateTree(*cobra.Command, []string) {
 		GenTapped:       eventTappedVal,
 		GenDoubleTapped: eventDoubleTappedVal,
 		GenSecondTapped: eventSecondTappedVal,
+		GenHover:        eventHoverVal,
+		GenMouse:        eventMouseVal,
+		GenTooltips:     tooltipsVal,
+		Checkable:       checkableVal,
+		MultiSelect:     multiSelectVal,
+		Draggable:       draggableVal,
+		Editable:        editableVal,
+		ContextMenu:     contextMenuVal,
+		Keyboard:        keyboardVal,
+		ModelType:       modelTypeVal,
+		ModelImport:     modelImportVal,
 	}
-	cobra.CheckErr(treeNodeTemplate.Execute(&nodeBuf, params))
-	nodeBuf.WriteString("\n\n")
 	cobra.CheckErr(treeTemplate.Execute(&treeBuf, params))
-	treeBuf.WriteString("\n\n")
-	if err := os.WriteFile(fileVal+"Node.go", nodeBuf.Bytes(), 0766); err != nil {
-		log.Printf("Error writing to '%s': %v\n", fileVal, err)
-		return
-	}
-	if err := os.WriteFile(fileVal+"Tree.go", treeBuf.Bytes(), 0766); err != nil {
+	// Optional sections leave uneven whitespace behind, so the output is formatted before it's written.
+	treeSrc, err := format.Source(treeBuf.Bytes())
+	cobra.CheckErr(err)
+	if err := os.WriteFile(fileVal+"Tree.go", treeSrc, 0766); err != nil {
 		log.Printf("Error writing to '%s': %v\n", fileVal, err)
 		return
 	}
@@ -78,25 +118,39 @@ type TreeGenParams struct {
 	GenTapped       bool
 	GenDoubleTapped bool
 	GenSecondTapped bool
+	GenHover        bool
+	GenMouse        bool
+	GenTooltips     bool
+	Checkable       bool
+	MultiSelect     bool
+	Draggable       bool
+	Editable        bool
+	ContextMenu     bool
+	Keyboard        bool
+	ModelType       string
+	ModelImport     string
 }
 
 func (p *TreeGenParams) TypeBaseTitle() string {
 	return strings.Title(p.TypeBase)
 }
 
-func (p *TreeGenParams) TypeBaseHidden() string {
-	splits := strings.SplitN(p.TypeBase, "", 2)
-	switch len(splits) {
-	case 2:
-		return strings.ToLower(splits[0]) + splits[1]
-	case 1:
-		return strings.ToLower(splits[0])
-	default:
-		panic("Unexpectedly blank type base")
+// Typed indicates that the tree should be backed by a generation.TypedRegistry.
+func (p *TreeGenParams) Typed() bool {
+	return p.ModelType != ""
+}
+
+// ImportsDesktop indicates that the generated tree uses desktop events.
+func (p *TreeGenParams) ImportsDesktop() bool {
+	return p.GenHover || p.GenMouse
+}
+
+// Model returns the model type used in generated signatures.
+func (p *TreeGenParams) Model() string {
+	if p.Typed() {
+		return p.ModelType
 	}
+	return "generation.TreeModel"
 }
 
-var (
-	treeNodeTemplate = template.Must(template.New("generateTreeNode").Parse(treeNodeText))
-	treeTemplate     = template.Must(template.New("generateTree").Parse(treeText))
-)
+var treeTemplate = template.Must(template.New("generateTree").Parse(treeText))
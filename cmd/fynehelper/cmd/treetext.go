@@ -1,202 +1,333 @@
 package cmd
 
 const (
-	treeNodeText = `// Code generated by fynehelper; DO NOT EDIT.
+	treeText = `// Code generated by fynehelper; DO NOT EDIT.
 
 package {{ .Package }}
 
 import (
-	"sync"
-
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/layout"
+{{- if .ImportsDesktop }}
+	"fyne.io/fyne/v2/driver/desktop"
+{{- end }}
 	"fyne.io/fyne/v2/widget"
 	"github.com/drognisep/fynehelpers/generation"
-	"github.com/drognisep/fynehelpers/layouthelp"
+{{- if .ModelImport }}
+	"{{ .ModelImport }}"
+{{- end }}
 )
 
-var _ fyne.Widget = (*{{ .TypeBaseHidden }}Node)(nil)
+var _ fyne.Widget = (*{{ .TypeBaseTitle }}Tree)(nil)
+
+// {{ .TypeBaseTitle }}Tree is a generation.Tree with the features it was generated with, and handlers that receive
+// models as {{ .Model }}. The embedded Tree manages IDs through its registry, and is the gatekeeper for all widget and
+// model mutations.
+type {{ .TypeBaseTitle }}Tree struct {
+	*generation.Tree
+
 {{- if .GenTapped }}
-var _ fyne.Tappable = (*{{ .TypeBaseHidden }}Node)(nil)
-{{- end -}}
+	OnTapped          func(id widget.TreeNodeID, model {{ .Model }}, event *fyne.PointEvent) // OnTapped is called when a node is tapped, after it's selected.
+{{- end }}
 {{- if .GenDoubleTapped }}
-var _ fyne.DoubleTappable = (*{{ .TypeBaseHidden }}Node)(nil)
-{{- end -}}
+	OnDoubleTapped    func(id widget.TreeNodeID, model {{ .Model }}, event *fyne.PointEvent) // OnDoubleTapped is called when a node is double tapped.
+{{- end }}
 {{- if .GenSecondTapped }}
-var _ fyne.SecondaryTappable = (*{{ .TypeBaseHidden }}Node)(nil)
-{{- end}}
-type {{ .TypeBaseHidden }}Node struct {
-	widget.BaseWidget
-
-	mux    sync.RWMutex
-	id     widget.TreeNodeID
-	render *{{ .TypeBaseHidden }}NodeRenderer
-	tree   *{{ .TypeBaseTitle }}Tree
+	OnTappedSecondary func(id widget.TreeNodeID, model {{ .Model }}, event *fyne.PointEvent) // OnTappedSecondary is called when a node is secondary tapped{{ if .ContextMenu }}, before the context menu is shown{{ end }}.
+{{- end }}
+{{- if .GenHover }}
+	OnMouseIn    func(id widget.TreeNodeID, model {{ .Model }}, event *desktop.MouseEvent) // OnMouseIn is called when the pointer enters a node.
+	OnMouseMoved func(id widget.TreeNodeID, model {{ .Model }}, event *desktop.MouseEvent) // OnMouseMoved is called when the pointer moves over a node.
+	OnMouseOut   func(id widget.TreeNodeID, model {{ .Model }})                            // OnMouseOut is called when the pointer leaves a node.
+{{- end }}
+{{- if .GenMouse }}
+	OnMouseDown func(id widget.TreeNodeID, model {{ .Model }}, event *desktop.MouseEvent) // OnMouseDown is called when a mouse button is pressed on a node, with the button and modifier.
+	OnMouseUp   func(id widget.TreeNodeID, model {{ .Model }}, event *desktop.MouseEvent) // OnMouseUp is called when a mouse button is released on a node, with the button and modifier.
+{{- end }}
+{{- if .ContextMenu }}
+	MenuFor func(id widget.TreeNodeID, model {{ .Model }}) *fyne.Menu // MenuFor provides the context menu shown when a node is secondary tapped. Items from models implementing generation.ContextMenuTreeModel are added after it.
+{{- end }}
+{{- if .Keyboard }}
+	OnActivate func(id widget.TreeNodeID, model {{ .Model }}) // OnActivate is called when Enter is pressed on the current node. Branches are opened or closed instead if it's nil.
+{{- end }}
+{{- if .Checkable }}
+	OnCheckChanged func(id widget.TreeNodeID, model {{ .Model }}, state generation.CheckState) // OnCheckChanged is called for every node whose check state changes, including ancestors and descendants of a checked node.
+{{- end }}
+{{- if .Draggable }}
+	OnDrop func(id widget.TreeNodeID, model {{ .Model }}, target generation.DropTarget) bool // OnDrop is called before a dragged node is moved, and may return false to veto the drop.
+{{- end }}
+{{- if .Typed }}
+
+	registry *generation.TypedRegistry[{{ .ModelType }}]
+{{- end }}
+	options  []generation.TreeOption // options configure the embedded Tree as it's created.
 }
 
-func (t *{{ .TypeBaseHidden }}Node) CreateRenderer() fyne.WidgetRenderer {
-	t.render = new{{ .TypeBaseTitle }}NodeRenderer()
-	return t.render
+// New{{ .TypeBaseTitle }}Tree creates a tree configured by options, and adds the roots given with
+// With{{ .TypeBaseTitle }}Roots to its registry. Roots that can't be added are passed to the error handler.
+func New{{ .TypeBaseTitle }}Tree(options ...{{ .TypeBaseTitle }}TreeOption) *{{ .TypeBaseTitle }}Tree {
+	tree := new{{ .TypeBaseTitle }}Tree(options)
+	tree.setTree(generation.NewTree(tree.options...))
+	return tree
 }
 
-func new{{ .TypeBaseTitle }}Node(tree *{{ .TypeBaseTitle }}Tree) *{{ .TypeBaseHidden }}Node {
-	node := &{{ .TypeBaseHidden }}Node{
-		tree: tree,
+// New{{ .TypeBaseTitle }}TreeE creates a tree like New{{ .TypeBaseTitle }}Tree, but returns the first error from adding
+// roots instead of passing it to the error handler.
+func New{{ .TypeBaseTitle }}TreeE(options ...{{ .TypeBaseTitle }}TreeOption) (*{{ .TypeBaseTitle }}Tree, error) {
+	tree := new{{ .TypeBaseTitle }}Tree(options)
+	inner, err := generation.NewTreeE(tree.options...)
+	if err != nil {
+		return nil, err
 	}
-	node.ExtendBaseWidget(node)
-	return node
+	tree.setTree(inner)
+	return tree, nil
 }
 
-func (t *{{ .TypeBaseHidden }}Node) update(id widget.TreeNodeID, model generation.TreeModel) {
-	t.mux.Lock()
-	t.id = id
-	if t.render != nil {
-		t.render.label.SetText(model.DisplayString())
-		t.render.icon.SetResource(model.DisplayIcon())
+func new{{ .TypeBaseTitle }}Tree(options []{{ .TypeBaseTitle }}TreeOption) *{{ .TypeBaseTitle }}Tree {
+	tree := &{{ .TypeBaseTitle }}Tree{}
+	// Handlers are passed to the embedded Tree as they're called, so they may be set at any time.
+	tree.options = []generation.TreeOption{
+		generation.WithExtendingWidget(tree),
+{{- if .Checkable }}
+		generation.WithCheckboxes(),
+		generation.WithOnCheckChanged(func(id widget.TreeNodeID, _ generation.TreeModel, state generation.CheckState) {
+			if tree.OnCheckChanged != nil {
+				tree.OnCheckChanged(id, tree.Node(id), state)
+			}
+		}),
+{{- end }}
+{{- if .MultiSelect }}
+		generation.WithMultiSelect(),
+{{- end }}
+{{- if .Draggable }}
+		generation.WithDragAndDrop(),
+		generation.WithOnDrop(func(id widget.TreeNodeID, _ generation.TreeModel, target generation.DropTarget) bool {
+			return tree.OnDrop == nil || tree.OnDrop(id, tree.Node(id), target)
+		}),
+{{- end }}
+{{- if .Editable }}
+		generation.WithRename(),
+{{- end }}
+{{- if .Keyboard }}
+		generation.WithKeyboard(),
+{{- end }}
+{{- if .GenTooltips }}
+		generation.WithTooltips(),
+{{- end }}
+{{- if .ContextMenu }}
+		generation.WithContextMenu(func(id widget.TreeNodeID, _ generation.TreeModel) *fyne.Menu {
+			if tree.MenuFor == nil {
+				return nil
+			}
+			return tree.MenuFor(id, tree.Node(id))
+		}),
+{{- end }}
+{{- if .GenTapped }}
+		generation.WithOnTapped(func(id widget.TreeNodeID, _ generation.TreeModel, event *fyne.PointEvent) {
+			tree.Tapped(id, event)
+		}),
+{{- end }}
+{{- if .GenDoubleTapped }}
+		generation.WithOnDoubleTapped(func(id widget.TreeNodeID, _ generation.TreeModel, event *fyne.PointEvent) {
+			tree.DoubleTapped(id, event)
+		}),
+{{- end }}
+{{- if .GenSecondTapped }}
+		generation.WithOnTappedSecondary(func(id widget.TreeNodeID, _ generation.TreeModel, event *fyne.PointEvent) {
+			tree.TappedSecondary(id, event)
+		}),
+{{- end }}
+{{- if .GenHover }}
+		generation.WithOnHover(
+			func(id widget.TreeNodeID, _ generation.TreeModel, event *desktop.MouseEvent) {
+				tree.MouseIn(id, event)
+			},
+			func(id widget.TreeNodeID, _ generation.TreeModel, event *desktop.MouseEvent) {
+				tree.MouseMoved(id, event)
+			},
+			func(id widget.TreeNodeID, _ generation.TreeModel) {
+				tree.MouseOut(id)
+			},
+		),
+{{- end }}
+{{- if .GenMouse }}
+		generation.WithOnMouse(
+			func(id widget.TreeNodeID, _ generation.TreeModel, event *desktop.MouseEvent) {
+				tree.MouseDown(id, event)
+			},
+			func(id widget.TreeNodeID, _ generation.TreeModel, event *desktop.MouseEvent) {
+				tree.MouseUp(id, event)
+			},
+		),
+{{- end }}
+	}
+	for _, option := range options {
+		option(tree)
+	}
+{{- if .Typed }}
+	if tree.registry == nil {
+		tree.registry = generation.NewTypedRegistry[{{ .ModelType }}]()
 	}
-	t.mux.Unlock()
-	t.Refresh()
+	tree.options = append(tree.options, generation.WithRegistry(tree.registry.TreeModelRegistry))
+{{- end }}
+	return tree
 }
 
-{{- if .GenTapped }}
-
-func (t *{{ .TypeBaseHidden }}Node) Tapped(event *fyne.PointEvent) {
-	t.mux.RLock()
-	id := t.id
-	t.mux.RUnlock()
-	t.tree.Tapped(id, event)
+// setTree embeds the Tree created from the options.
+func (t *{{ .TypeBaseTitle }}Tree) setTree(tree *generation.Tree) {
+	t.Tree = tree
+	t.options = nil
+{{- if .Keyboard }}
+	t.KeyActions[fyne.KeyReturn] = t.Activate
+	t.KeyActions[fyne.KeyEnter] = t.Activate
+{{- end }}
 }
-{{end -}}
 
-{{- if .GenDoubleTapped }}
+// {{ .TypeBaseTitle }}TreeOption configures a {{ .TypeBaseTitle }}Tree as it's created.
+type {{ .TypeBaseTitle }}TreeOption func(tree *{{ .TypeBaseTitle }}Tree)
 
-func (t *{{ .TypeBaseHidden }}Node) DoubleTapped(event *fyne.PointEvent) {
-	t.mux.RLock()
-	id := t.id
-	t.mux.RUnlock()
-	t.tree.DoubleTapped(id, event)
+// With{{ .TypeBaseTitle }}Roots adds roots to the tree's registry once every other option has been applied.
+func With{{ .TypeBaseTitle }}Roots(roots ...{{ .Model }}) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		for _, root := range roots {
+			tree.options = append(tree.options, generation.WithRoots(root))
+		}
+	}
 }
-{{end -}}
-
-{{- if .GenSecondTapped }}
 
-func (t *{{ .TypeBaseHidden }}Node) TappedSecondary(event *fyne.PointEvent) {
-	t.mux.RLock()
-	id := t.id
-	t.mux.RUnlock()
-	t.tree.TappedSecondary(id, event)
+// With{{ .TypeBaseTitle }}ErrorHandler handles errors the tree can't return, like failing to add a root or drop a node.
+// They're logged if handler is nil.
+func With{{ .TypeBaseTitle }}ErrorHandler(handler func(err error)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.options = append(tree.options, generation.WithErrorHandler(handler))
+	}
 }
-{{end}}
-var _ fyne.WidgetRenderer = (*{{ .TypeBaseHidden }}NodeRenderer)(nil)
 
-type {{ .TypeBaseHidden }}NodeRenderer struct {
-	icon    *widget.Icon
-	label   *widget.Label
-	layout  fyne.Layout
-	objects []fyne.CanvasObject
+// With{{ .TypeBaseTitle }}Registry displays the nodes in an existing registry, instead of creating an empty one.
+func With{{ .TypeBaseTitle }}Registry(registry {{ if .Typed }}*generation.TypedRegistry[{{ .ModelType }}]{{ else }}*generation.TreeModelRegistry{{ end }}) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+{{- if .Typed }}
+		tree.registry = registry
+{{- else }}
+		tree.options = append(tree.options, generation.WithRegistry(registry))
+{{- end }}
+	}
 }
 
-func new{{ .TypeBaseTitle }}NodeRenderer() *{{ .TypeBaseHidden }}NodeRenderer {
-	render := &{{ .TypeBaseHidden }}NodeRenderer{
-		icon: &widget.Icon{},
-		label: &widget.Label{
-			Alignment: fyne.TextAlignLeading,
-			TextStyle: fyne.TextStyle{},
-		},
-		layout: layout.NewHBoxLayout(),
+// With{{ .TypeBaseTitle }}IDStrategy sets the strategy the tree's registry uses to create node IDs, see
+// generation.TreeModelRegistry.SetIDStrategy.
+func With{{ .TypeBaseTitle }}IDStrategy(strategy generation.IDStrategy) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.options = append(tree.options, generation.WithIDStrategy(strategy))
 	}
-	render.objects = []fyne.CanvasObject{render.icon, render.label}
-	return render
 }
 
-func (r *{{ .TypeBaseHidden }}NodeRenderer) Destroy() {
-	r.icon = nil
-	r.label = nil
-	r.layout = nil
-	r.objects = nil
+// With{{ .TypeBaseTitle }}NodeContent displays models with content created by factory, instead of an icon and label.
+func With{{ .TypeBaseTitle }}NodeContent(factory generation.NodeContentFactory) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.options = append(tree.options, generation.WithNodeContent(factory))
+	}
 }
+{{- if .GenTapped }}
 
-func (r *{{ .TypeBaseHidden }}NodeRenderer) Layout(parent fyne.Size) {
-	r.layout.Layout(r.objects, parent)
+// With{{ .TypeBaseTitle }}OnTapped sets OnTapped.
+func With{{ .TypeBaseTitle }}OnTapped(handler func(id widget.TreeNodeID, model {{ .Model }}, event *fyne.PointEvent)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnTapped = handler
+	}
 }
+{{- end }}
+{{- if .GenDoubleTapped }}
 
-func (r *{{ .TypeBaseHidden }}NodeRenderer) MinSize() fyne.Size {
-	return layouthelp.AccumulateWidth(r.icon.MinSize(), r.label.MinSize())
+// With{{ .TypeBaseTitle }}OnDoubleTapped sets OnDoubleTapped.
+func With{{ .TypeBaseTitle }}OnDoubleTapped(handler func(id widget.TreeNodeID, model {{ .Model }}, event *fyne.PointEvent)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnDoubleTapped = handler
+	}
 }
+{{- end }}
+{{- if .GenSecondTapped }}
 
-func (r *{{ .TypeBaseHidden }}NodeRenderer) Objects() []fyne.CanvasObject {
-	return r.objects
+// With{{ .TypeBaseTitle }}OnTappedSecondary sets OnTappedSecondary.
+func With{{ .TypeBaseTitle }}OnTappedSecondary(handler func(id widget.TreeNodeID, model {{ .Model }}, event *fyne.PointEvent)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnTappedSecondary = handler
+	}
 }
-
-func (r *{{ .TypeBaseHidden }}NodeRenderer) Refresh() {
-	for _, obj := range r.objects {
-		obj.Refresh()
+{{- end }}
+{{- if .GenHover }}
+
+// With{{ .TypeBaseTitle }}OnHover sets OnMouseIn, OnMouseMoved and OnMouseOut. Any of them may be nil.
+func With{{ .TypeBaseTitle }}OnHover(in, moved func(id widget.TreeNodeID, model {{ .Model }}, event *desktop.MouseEvent), out func(id widget.TreeNodeID, model {{ .Model }})) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnMouseIn = in
+		tree.OnMouseMoved = moved
+		tree.OnMouseOut = out
 	}
 }
-`
-	treeText = `// Code generated by fynehelper; DO NOT EDIT.
-
-package {{ .Package }}
-
-import (
-	"log"
+{{- end }}
+{{- if .GenMouse }}
+
+// With{{ .TypeBaseTitle }}OnMouse sets OnMouseDown and OnMouseUp. Either of them may be nil.
+func With{{ .TypeBaseTitle }}OnMouse(down, up func(id widget.TreeNodeID, model {{ .Model }}, event *desktop.MouseEvent)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnMouseDown = down
+		tree.OnMouseUp = up
+	}
+}
+{{- end }}
+{{- if .ContextMenu }}
 
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/widget"
-	"github.com/drognisep/fynehelpers/generation"
-)
+// With{{ .TypeBaseTitle }}MenuFor sets MenuFor.
+func With{{ .TypeBaseTitle }}MenuFor(menuFor func(id widget.TreeNodeID, model {{ .Model }}) *fyne.Menu) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.MenuFor = menuFor
+	}
+}
+{{- end }}
+{{- if .Keyboard }}
 
-var _ fyne.CanvasObject = (*{{ .TypeBaseTitle }}Tree)(nil)
+// With{{ .TypeBaseTitle }}OnActivate sets OnActivate.
+func With{{ .TypeBaseTitle }}OnActivate(handler func(id widget.TreeNodeID, model {{ .Model }})) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnActivate = handler
+	}
+}
+{{- end }}
+{{- if .Checkable }}
 
-// {{ .TypeBaseTitle }}Tree is a widget.Tree implementation that manages IDs through generation.TreeModelRegistry.
-// This is designed to be the gatekeeper for all widget and model mutations.
-type {{ .TypeBaseTitle }}Tree struct {
-	widget.Tree
-	*generation.TreeModelRegistry
+// With{{ .TypeBaseTitle }}OnCheckChanged sets OnCheckChanged.
+func With{{ .TypeBaseTitle }}OnCheckChanged(handler func(id widget.TreeNodeID, model {{ .Model }}, state generation.CheckState)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnCheckChanged = handler
+	}
+}
+{{- end }}
+{{- if .MultiSelect }}
 
-{{- if .GenTapped }}
-	OnTapped          func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnTapped is called by the {{ .TypeBaseHidden }}Node that receives an event from Fyne.
-{{end -}}
-{{- if .GenDoubleTapped }}
-	OnDoubleTapped    func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnDoubleTapped is called by the {{ .TypeBaseHidden }}Node that receives an event from Fyne.
-{{end -}}
-{{- if .GenSecondTapped }}
-	OnTappedSecondary func(id widget.TreeNodeID, model generation.TreeModel, event *fyne.PointEvent) // OnTappedSecondary is called by the {{ .TypeBaseHidden }}Node that receives an event from Fyne.
-{{end}}
-}
-
-// New{{ .TypeBaseTitle }}Tree initializes the tree and adds all modelRoots to the registry.
-func New{{ .TypeBaseTitle }}Tree(modelRoots ...generation.TreeModel) *{{ .TypeBaseTitle }}Tree {
-	tree := &{{ .TypeBaseTitle }}Tree{
-		TreeModelRegistry: generation.NewTreeModelRegistry(),
-	}
-	tree.Tree = widget.Tree{
-		ChildUIDs: tree.Children,
-		CreateNode: func(bool) (o fyne.CanvasObject) {
-			return new{{ .TypeBaseTitle }}Node(tree)
-		},
-		IsBranch: tree.HasChildren,
-		UpdateNode: func(id widget.TreeNodeID, isBranch bool, node fyne.CanvasObject) {
-			treeModel, ok := node.(*{{ .TypeBaseHidden }}Node)
-			if !ok {
-				return
-			}
-			modelNode := tree.Node(id)
-			if modelNode == nil {
-				return
-			}
-			treeModel.update(id, modelNode)
-		},
+// With{{ .TypeBaseTitle }}OnSelectionChanged sets OnSelectionChanged.
+func With{{ .TypeBaseTitle }}OnSelectionChanged(handler func(selected []widget.TreeNodeID)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.options = append(tree.options, generation.WithOnSelectionChanged(handler))
 	}
-	for _, root := range modelRoots {
-		if _, err := tree.AddChild("", root); err != nil {
-			log.Printf("Error adding model root: %v\n%v\n", err, root)
-		}
+}
+{{- else }}
+
+// With{{ .TypeBaseTitle }}OnSelected sets OnSelected and OnUnselected. Either of them may be nil.
+func With{{ .TypeBaseTitle }}OnSelected(selected, unselected func(id widget.TreeNodeID)) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.options = append(tree.options, generation.WithOnSelected(selected, unselected))
 	}
-	tree.ExtendBaseWidget(tree)
-	return tree
 }
+{{- end }}
+{{- if .Draggable }}
 
+// With{{ .TypeBaseTitle }}OnDrop sets OnDrop.
+func With{{ .TypeBaseTitle }}OnDrop(handler func(id widget.TreeNodeID, model {{ .Model }}, target generation.DropTarget) bool) {{ .TypeBaseTitle }}TreeOption {
+	return func(tree *{{ .TypeBaseTitle }}Tree) {
+		tree.OnDrop = handler
+	}
+}
+{{- end }}
 {{- if .GenTapped }}
 
 func (t *{{ .TypeBaseTitle }}Tree) Tapped(id widget.TreeNodeID, event *fyne.PointEvent) {
@@ -204,8 +335,7 @@ func (t *{{ .TypeBaseTitle }}Tree) Tapped(id widget.TreeNodeID, event *fyne.Poin
 		t.OnTapped(id, t.Node(id), event)
 	}
 }
-{{end -}}
-
+{{- end }}
 {{- if .GenDoubleTapped }}
 
 func (t *{{ .TypeBaseTitle }}Tree) DoubleTapped(id widget.TreeNodeID, event *fyne.PointEvent) {
@@ -213,24 +343,78 @@ func (t *{{ .TypeBaseTitle }}Tree) DoubleTapped(id widget.TreeNodeID, event *fyn
 		t.OnDoubleTapped(id, t.Node(id), event)
 	}
 }
-{{end -}}
-
-{{- if .GenSecondTapped}}
+{{- end }}
+{{- if .GenSecondTapped }}
 
 func (t *{{ .TypeBaseTitle }}Tree) TappedSecondary(id widget.TreeNodeID, event *fyne.PointEvent) {
 	if t.OnTappedSecondary != nil {
 		t.OnTappedSecondary(id, t.Node(id), event)
 	}
 }
-{{end}}
-func (t *{{ .TypeBaseTitle }}Tree) AddChild(parentID widget.TreeNodeID, data generation.TreeModel) (widget.TreeNodeID, error) {
-	defer t.Refresh()
-	return t.TreeModelRegistry.AddChild(parentID, data)
+{{- end }}
+{{- if .GenHover }}
+
+func (t *{{ .TypeBaseTitle }}Tree) MouseIn(id widget.TreeNodeID, event *desktop.MouseEvent) {
+	if t.OnMouseIn != nil {
+		t.OnMouseIn(id, t.Node(id), event)
+	}
+}
+
+func (t *{{ .TypeBaseTitle }}Tree) MouseMoved(id widget.TreeNodeID, event *desktop.MouseEvent) {
+	if t.OnMouseMoved != nil {
+		t.OnMouseMoved(id, t.Node(id), event)
+	}
+}
+
+func (t *{{ .TypeBaseTitle }}Tree) MouseOut(id widget.TreeNodeID) {
+	if t.OnMouseOut != nil {
+		t.OnMouseOut(id, t.Node(id))
+	}
+}
+{{- end }}
+{{- if .GenMouse }}
+
+func (t *{{ .TypeBaseTitle }}Tree) MouseDown(id widget.TreeNodeID, event *desktop.MouseEvent) {
+	if t.OnMouseDown != nil {
+		t.OnMouseDown(id, t.Node(id), event)
+	}
+}
+
+func (t *{{ .TypeBaseTitle }}Tree) MouseUp(id widget.TreeNodeID, event *desktop.MouseEvent) {
+	if t.OnMouseUp != nil {
+		t.OnMouseUp(id, t.Node(id), event)
+	}
+}
+{{- end }}
+{{- if .Keyboard }}
+
+// Activate calls OnActivate for id, or opens or closes it if it's a branch and OnActivate is nil.
+func (t *{{ .TypeBaseTitle }}Tree) Activate(id widget.TreeNodeID) {
+	if t.OnActivate == nil || id == generation.ModelRoot {
+		t.Tree.Activate(id)
+		return
+	}
+	t.OnActivate(id, t.Node(id))
+}
+{{- end }}
+{{- if .Typed }}
+
+// AddChild registers data as a child of parentID, and refreshes the tree.
+func (t *{{ .TypeBaseTitle }}Tree) AddChild(parentID widget.TreeNodeID, data {{ .ModelType }}) (widget.TreeNodeID, error) {
+	return t.Tree.AddChild(parentID, data)
+}
+
+// Node returns the model registered with nodeID, or its zero value if it doesn't exist or isn't a
+// {{ .ModelType }}.
+func (t *{{ .TypeBaseTitle }}Tree) Node(nodeID widget.TreeNodeID) {{ .ModelType }} {
+	return t.registry.Node(nodeID)
 }
 
-func (t *{{ .TypeBaseTitle }}Tree) RemoveChild(dataID widget.TreeNodeID) {
-	defer t.Refresh()
-	t.TreeModelRegistry.RemoveChild(dataID)
+// Walk traverses the registered tree, depth-first. Nodes that aren't a {{ .ModelType }} are passed to walker as its
+// zero value. Attempting to modify the tree while walking will result in a deadlock.
+func (t *{{ .TypeBaseTitle }}Tree) Walk(walker generation.TypedWalkFunc[{{ .ModelType }}]) {
+	t.registry.Walk(walker)
 }
+{{- end }}
 `
 )
@@ -0,0 +1,290 @@
+package generation
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var _ fyne.Widget = (*Breadcrumb)(nil)
+
+// breadcrumbSeparator is shown between segments.
+const breadcrumbSeparator = "›"
+
+// Breadcrumb shows the path from a node's root down to the node, with a button for each node on the way. Segments in
+// the middle of the path are collapsed into an overflow menu when there isn't enough room to show all of them. It
+// follows changes to the registry, and shows the closest remaining ancestor if the node is removed.
+type Breadcrumb struct {
+	widget.BaseWidget
+	OnTapped func(id widget.TreeNodeID) // OnTapped is called with the ID of the segment tapped, either directly or from the overflow menu.
+
+	registry *TreeModelRegistry
+	unlisten func() // unlisten removes the registry listener.
+	mux      sync.RWMutex
+	path     []widget.TreeNodeID
+}
+
+// NewBreadcrumb creates a Breadcrumb showing the path to nodeID in registry.
+func NewBreadcrumb(registry *TreeModelRegistry, nodeID widget.TreeNodeID) *Breadcrumb {
+	b := &Breadcrumb{
+		registry: registry,
+	}
+	b.path = b.pathTo(nodeID)
+	b.unlisten = registry.AddListener(b.registryChanged)
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+// Close stops following changes to the registry, so a Breadcrumb that's no longer shown doesn't outlive it. The path
+// that's shown is left as it is.
+func (b *Breadcrumb) Close() {
+	b.unlisten()
+}
+
+// Node returns the ID of the node at the end of the path, or an empty ID if nothing is shown.
+func (b *Breadcrumb) Node() widget.TreeNodeID {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	if len(b.path) == 0 {
+		return ModelRoot
+	}
+	return b.path[len(b.path)-1]
+}
+
+// SetNode shows the path to nodeID, or nothing if it isn't registered.
+func (b *Breadcrumb) SetNode(nodeID widget.TreeNodeID) {
+	path := b.pathTo(nodeID)
+	b.mux.Lock()
+	b.path = path
+	b.mux.Unlock()
+	b.Refresh()
+}
+
+// Path returns the IDs of every segment, starting with the root.
+func (b *Breadcrumb) Path() []widget.TreeNodeID {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return append([]widget.TreeNodeID(nil), b.path...)
+}
+
+// Bind shows the path to nodes as they're selected in a tree, where onSelected is the address of the tree's
+// OnSelected field, like &tree.OnSelected. The handler already set, if any, is still called.
+func (b *Breadcrumb) Bind(onSelected *func(id widget.TreeNodeID)) {
+	previous := *onSelected
+	*onSelected = func(id widget.TreeNodeID) {
+		b.SetNode(id)
+		if previous != nil {
+			previous(id)
+		}
+	}
+}
+
+func (b *Breadcrumb) pathTo(nodeID widget.TreeNodeID) []widget.TreeNodeID {
+	if b.registry.Node(nodeID) == nil {
+		return nil
+	}
+	return append(b.registry.Ancestors(nodeID), nodeID)
+}
+
+func (b *Breadcrumb) tapped(id widget.TreeNodeID) {
+	if b.OnTapped != nil {
+		b.OnTapped(id)
+	}
+}
+
+// registryChanged rebuilds the path, since any change could move, rename or remove one of its nodes. If the node is
+// gone, the deepest segment that's still registered is shown instead.
+func (b *Breadcrumb) registryChanged(_ RegistryEvent) {
+	b.mux.RLock()
+	previous := b.path
+	b.mux.RUnlock()
+	var path []widget.TreeNodeID
+	for i := len(previous) - 1; i >= 0 && path == nil; i-- {
+		path = b.pathTo(previous[i])
+	}
+	b.mux.Lock()
+	b.path = path
+	b.mux.Unlock()
+	b.Refresh()
+}
+
+func (b *Breadcrumb) CreateRenderer() fyne.WidgetRenderer {
+	b.ExtendBaseWidget(b)
+	r := &breadcrumbRenderer{
+		crumb: b,
+	}
+	r.overflow = widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), r.showOverflow)
+	r.overflow.Importance = widget.LowImportance
+	r.overflowSeparator = widget.NewLabel(breadcrumbSeparator)
+	r.Refresh()
+	return r
+}
+
+var _ fyne.WidgetRenderer = (*breadcrumbRenderer)(nil)
+
+type breadcrumbRenderer struct {
+	crumb             *Breadcrumb
+	path              []widget.TreeNodeID
+	segments          []*widget.Button
+	separators        []*widget.Label
+	overflow          *widget.Button
+	overflowSeparator *widget.Label
+	collapsed         []widget.TreeNodeID
+	objects           []fyne.CanvasObject
+}
+
+func (r *breadcrumbRenderer) Destroy() {
+}
+
+// Layout places the segments from left to right. If they don't fit, segments after the root are hidden until the rest
+// fit after the overflow button, but the root and the last segment are always shown.
+func (r *breadcrumbRenderer) Layout(size fyne.Size) {
+	n := len(r.segments)
+	hide := 0
+	if n > 2 && r.fullWidth() > size.Width {
+		for hide = 1; hide < n-2; hide++ {
+			if r.collapsedWidth(hide) <= size.Width {
+				break
+			}
+		}
+	}
+	r.collapsed = nil
+	if hide > 0 {
+		r.collapsed = append(r.collapsed, r.path[1:1+hide]...)
+	}
+
+	var x float32
+	place := func(obj fyne.CanvasObject) {
+		min := obj.MinSize()
+		obj.Resize(fyne.NewSize(min.Width, min.Height))
+		obj.Move(fyne.NewPos(x, (size.Height-min.Height)/2))
+		obj.Show()
+		x += min.Width
+	}
+	r.overflow.Hide()
+	r.overflowSeparator.Hide()
+	for i, segment := range r.segments {
+		if i > 0 && i <= hide {
+			segment.Hide()
+			r.separators[i-1].Hide()
+			continue
+		}
+		if i > 0 {
+			place(r.separators[i-1])
+		}
+		place(segment)
+		if i == 0 && hide > 0 {
+			place(r.overflowSeparator)
+			place(r.overflow)
+		}
+	}
+}
+
+// fullWidth is the width needed to show every segment.
+func (r *breadcrumbRenderer) fullWidth() float32 {
+	var width float32
+	for i, segment := range r.segments {
+		if i > 0 {
+			width += r.separators[i-1].MinSize().Width
+		}
+		width += segment.MinSize().Width
+	}
+	return width
+}
+
+// collapsedWidth is the width needed to show the segments with hide segments after the root moved to the overflow menu.
+func (r *breadcrumbRenderer) collapsedWidth(hide int) float32 {
+	width := r.segments[0].MinSize().Width + r.overflowSeparator.MinSize().Width + r.overflow.MinSize().Width
+	for i := 1 + hide; i < len(r.segments); i++ {
+		width += r.separators[i-1].MinSize().Width + r.segments[i].MinSize().Width
+	}
+	return width
+}
+
+func (r *breadcrumbRenderer) MinSize() fyne.Size {
+	n := len(r.segments)
+	if n == 0 {
+		return fyne.NewSize(0, 0)
+	}
+	var height float32
+	for _, obj := range r.objects {
+		height = fyne.Max(height, obj.MinSize().Height)
+	}
+	if n > 2 {
+		return fyne.NewSize(fyne.Min(r.fullWidth(), r.collapsedWidth(n-2)), height)
+	}
+	return fyne.NewSize(r.fullWidth(), height)
+}
+
+func (r *breadcrumbRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+// Refresh updates the segments from the current path and models, reusing the buttons already created.
+func (r *breadcrumbRenderer) Refresh() {
+	r.path = r.crumb.Path()
+	for len(r.segments) < len(r.path) {
+		i := len(r.segments)
+		r.segments = append(r.segments, widget.NewButton("", func() {
+			r.crumb.tapped(r.path[i])
+		}))
+		if i > 0 {
+			r.separators = append(r.separators, widget.NewLabel(breadcrumbSeparator))
+		}
+	}
+	r.segments = r.segments[:len(r.path)]
+	if len(r.path) > 0 {
+		r.separators = r.separators[:len(r.path)-1]
+	} else {
+		r.separators = r.separators[:0]
+	}
+
+	r.objects = r.objects[:0]
+	for i, segment := range r.segments {
+		model := r.crumb.registry.Node(r.path[i])
+		if model != nil {
+			segment.SetText(model.DisplayString())
+			segment.SetIcon(model.DisplayIcon())
+		}
+		segment.Importance = widget.LowImportance
+		if i == len(r.segments)-1 {
+			segment.Importance = widget.MediumImportance
+		}
+		r.objects = append(r.objects, segment)
+	}
+	for _, separator := range r.separators {
+		r.objects = append(r.objects, separator)
+	}
+	r.objects = append(r.objects, r.overflowSeparator, r.overflow)
+	r.Layout(r.crumb.Size())
+	for _, obj := range r.objects {
+		obj.Refresh()
+	}
+}
+
+// overflowMenu lists the collapsed segments.
+func (r *breadcrumbRenderer) overflowMenu() *fyne.Menu {
+	items := make([]*fyne.MenuItem, len(r.collapsed))
+	for i, id := range r.collapsed {
+		id := id
+		label := id
+		if model := r.crumb.registry.Node(id); model != nil {
+			label = model.DisplayString()
+		}
+		items[i] = fyne.NewMenuItem(label, func() {
+			r.crumb.tapped(id)
+		})
+	}
+	return fyne.NewMenu("", items...)
+}
+
+func (r *breadcrumbRenderer) showOverflow() {
+	c := fyne.CurrentApp().Driver().CanvasForObject(r.crumb)
+	if c == nil || len(r.collapsed) == 0 {
+		return
+	}
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(r.overflow)
+	widget.ShowPopUpMenuAtPosition(r.overflowMenu(), c, pos.Add(fyne.NewPos(0, r.overflow.Size().Height)))
+}
@@ -0,0 +1,161 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+// buildBreadcrumbRegistry registers root > a > b > c > d with path IDs.
+func buildBreadcrumbRegistry(t *testing.T) (*TreeModelRegistry, map[string]*ModelData) {
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	models := map[string]*ModelData{}
+	parentID := ModelRoot
+	for _, name := range []string{"root", "a", "b", "c", "d"} {
+		models[name] = &ModelData{Data: name}
+		id, err := reg.AddChild(parentID, models[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentID = id
+	}
+	return reg, models
+}
+
+func breadcrumbText(r *breadcrumbRenderer) []string {
+	var text []string
+	for _, segment := range r.segments {
+		if segment.Visible() {
+			text = append(text, segment.Text)
+		}
+	}
+	return text
+}
+
+func TestBreadcrumb_Path(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+	reg, _ := buildBreadcrumbRegistry(t)
+	crumb := NewBreadcrumb(reg, "root/a/b")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/b"}, crumb.Path())
+	assert.Equal(widget.TreeNodeID("root/a/b"), crumb.Node())
+
+	w := test.NewWindow(crumb)
+	defer w.Close()
+	w.Resize(fyne.NewSize(600, 100))
+	r := test.WidgetRenderer(crumb).(*breadcrumbRenderer)
+	assert.Equal([]string{"root", "a", "b"}, breadcrumbText(r))
+	assert.Equal(widget.MediumImportance, r.segments[2].Importance, "The node should stand out from its ancestors")
+	assert.False(r.overflow.Visible())
+
+	var tapped []widget.TreeNodeID
+	crumb.OnTapped = func(id widget.TreeNodeID) {
+		tapped = append(tapped, id)
+	}
+	test.Tap(r.segments[1])
+	assert.Equal([]widget.TreeNodeID{"root/a"}, tapped)
+
+	crumb.SetNode("missing")
+	assert.Empty(crumb.Path())
+	assert.Equal(ModelRoot, crumb.Node())
+	assert.Empty(breadcrumbText(r))
+}
+
+func TestBreadcrumb_Overflow(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	parentID := ModelRoot
+	for _, name := range []string{"root", "alpha", "bravo", "charlie", "delta"} {
+		id, err := reg.AddChild(parentID, &ModelData{Data: name})
+		assert.NoError(err)
+		parentID = id
+	}
+	crumb := NewBreadcrumb(reg, "root/alpha/bravo/charlie/delta")
+	w := test.NewWindow(crumb)
+	defer w.Close()
+	r := test.WidgetRenderer(crumb).(*breadcrumbRenderer)
+	w.Resize(fyne.NewSize(r.fullWidth()+2*theme.Padding(), 100))
+	assert.Equal([]string{"root", "alpha", "bravo", "charlie", "delta"}, breadcrumbText(r))
+
+	w.Resize(fyne.NewSize(r.collapsedWidth(1)+2*theme.Padding(), 100))
+	assert.Equal([]string{"root", "bravo", "charlie", "delta"}, breadcrumbText(r), "Segments after the root should be collapsed first")
+	assert.True(r.overflow.Visible())
+	assert.Equal([]widget.TreeNodeID{"root/alpha"}, r.collapsed)
+
+	w.Resize(fyne.NewSize(10, 100))
+	assert.Equal([]string{"root", "delta"}, breadcrumbText(r), "The root and node should always be shown")
+	assert.Equal([]widget.TreeNodeID{"root/alpha", "root/alpha/bravo", "root/alpha/bravo/charlie"}, r.collapsed)
+	assert.Equal(r.collapsedWidth(3), crumb.MinSize().Width)
+
+	var tapped []widget.TreeNodeID
+	crumb.OnTapped = func(id widget.TreeNodeID) {
+		tapped = append(tapped, id)
+	}
+	menu := r.overflowMenu()
+	assert.Len(menu.Items, 3)
+	assert.Equal("bravo", menu.Items[1].Label)
+	menu.Items[1].Action()
+	assert.Equal([]widget.TreeNodeID{"root/alpha/bravo"}, tapped)
+
+	test.Tap(r.overflow)
+	assert.NotNil(w.Canvas().Overlays().Top(), "Tapping the overflow button should show the collapsed segments")
+}
+
+func TestBreadcrumb_RegistryChanges(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+	reg, models := buildBreadcrumbRegistry(t)
+	crumb := NewBreadcrumb(reg, "root/a/b/c")
+	w := test.NewWindow(crumb)
+	defer w.Close()
+	w.Resize(fyne.NewSize(600, 100))
+	r := test.WidgetRenderer(crumb).(*breadcrumbRenderer)
+
+	models["a"].Data = "renamed"
+	reg.Updated("root/a")
+	assert.Equal([]string{"root", "renamed", "b", "c"}, breadcrumbText(r))
+
+	reg.RemoveChild("root/a/b")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a"}, crumb.Path(), "The closest remaining ancestor should be shown")
+	assert.Equal([]string{"root", "renamed"}, breadcrumbText(r))
+}
+
+func TestBreadcrumb_Close(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+	reg, models := buildBreadcrumbRegistry(t)
+	crumb := NewBreadcrumb(reg, "root/a/b/c")
+	w := test.NewWindow(crumb)
+	defer w.Close()
+	w.Resize(fyne.NewSize(600, 100))
+	r := test.WidgetRenderer(crumb).(*breadcrumbRenderer)
+
+	crumb.Close()
+	models["a"].Data = "renamed"
+	reg.Updated("root/a")
+	reg.RemoveChild("root/a/b")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/b", "root/a/b/c"}, crumb.Path(), "A closed Breadcrumb shouldn't follow the registry")
+	assert.Equal([]string{"root", "a", "b", "c"}, breadcrumbText(r))
+}
+
+func TestBreadcrumb_Bind(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, _ := buildTree(t)
+	crumb := NewBreadcrumb(tree.TreeModelRegistry, ModelRoot)
+	var selected []widget.TreeNodeID
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		selected = append(selected, id)
+	}
+	crumb.Bind(&tree.OnSelected)
+
+	tree.Select(ids["a1"])
+	assert.Equal([]widget.TreeNodeID{ids["root"], ids["a"], ids["a1"]}, crumb.Path())
+	assert.Equal([]widget.TreeNodeID{ids["a1"]}, selected)
+}
@@ -0,0 +1,178 @@
+package generation
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// CheckState is the state of a node's checkbox.
+type CheckState int
+
+const (
+	Unchecked     CheckState = iota // Unchecked nodes have no checked descendants.
+	Checked                         // Checked nodes have only checked descendants.
+	Indeterminate                   // Indeterminate nodes have both checked and unchecked descendants.
+)
+
+// CheckSet stores the check state of nodes in a TreeModelRegistry. Checking a node checks all of its descendants, and
+// ancestors are updated to reflect the state of their children.
+// Nodes added under a checked parent are checked too, so a checked branch stays checked as it grows.
+type CheckSet struct {
+	OnChanged func(changed []widget.TreeNodeID) // OnChanged is called with every node whose state changed, after the change is complete.
+
+	mux      sync.RWMutex
+	registry *TreeModelRegistry
+	unlisten func() // unlisten removes the registry listener.
+	states   map[widget.TreeNodeID]CheckState
+}
+
+// NewCheckSet creates a CheckSet that follows changes to registry.
+func NewCheckSet(registry *TreeModelRegistry) *CheckSet {
+	checks := &CheckSet{
+		registry: registry,
+		states:   map[widget.TreeNodeID]CheckState{},
+	}
+	checks.unlisten = registry.AddListener(checks.registryChanged)
+	return checks
+}
+
+// Close stops following changes to the registry, so a CheckSet that's no longer used doesn't outlive it.
+func (c *CheckSet) Close() {
+	c.unlisten()
+}
+
+// State returns the check state of id. Unknown nodes are Unchecked.
+func (c *CheckSet) State(id widget.TreeNodeID) CheckState {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.states[id]
+}
+
+// SetChecked checks or unchecks id and all of its descendants, updating ancestors to match.
+// The IDs of all nodes that changed state are returned.
+func (c *CheckSet) SetChecked(id widget.TreeNodeID, checked bool) []widget.TreeNodeID {
+	if id == ModelRoot || c.registry.Node(id) == nil {
+		return nil
+	}
+	state := Unchecked
+	if checked {
+		state = Checked
+	}
+	c.mux.Lock()
+	changed := c.setSubtree(id, state, nil)
+	changed = c.updateAncestors(c.registry.Parent(id), changed)
+	c.mux.Unlock()
+	c.changed(changed)
+	return changed
+}
+
+// CheckedIDs returns the IDs of all checked nodes, in depth-first order.
+func (c *CheckSet) CheckedIDs() []widget.TreeNodeID {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	var checked []widget.TreeNodeID
+	c.registry.Walk(func(_ widget.TreeNodeID, _ TreeModel, nodeID widget.TreeNodeID, _ TreeModel) {
+		if c.states[nodeID] == Checked {
+			checked = append(checked, nodeID)
+		}
+	})
+	return checked
+}
+
+func (c *CheckSet) setSubtree(id widget.TreeNodeID, state CheckState, changed []widget.TreeNodeID) []widget.TreeNodeID {
+	if c.setState(id, state) {
+		changed = append(changed, id)
+	}
+	for _, childID := range c.registry.Children(id) {
+		changed = c.setSubtree(childID, state, changed)
+	}
+	return changed
+}
+
+func (c *CheckSet) updateAncestors(id widget.TreeNodeID, changed []widget.TreeNodeID) []widget.TreeNodeID {
+	for ; id != ModelRoot; id = c.registry.Parent(id) {
+		if !c.setState(id, c.aggregate(id)) {
+			break
+		}
+		changed = append(changed, id)
+	}
+	return changed
+}
+
+// aggregate computes the state of a parent from the states of its children. A node left without children keeps its
+// state if it's checked, and is otherwise unchecked, since only children can make it indeterminate.
+func (c *CheckSet) aggregate(id widget.TreeNodeID) CheckState {
+	children := c.registry.Children(id)
+	if len(children) == 0 {
+		if c.states[id] == Checked {
+			return Checked
+		}
+		return Unchecked
+	}
+	var checked, unchecked bool
+	for _, childID := range children {
+		switch c.states[childID] {
+		case Checked:
+			checked = true
+		case Unchecked:
+			unchecked = true
+		default:
+			return Indeterminate
+		}
+	}
+	switch {
+	case checked && unchecked:
+		return Indeterminate
+	case checked:
+		return Checked
+	default:
+		return Unchecked
+	}
+}
+
+func (c *CheckSet) setState(id widget.TreeNodeID, state CheckState) bool {
+	if c.states[id] == state {
+		return false
+	}
+	if state == Unchecked {
+		delete(c.states, id)
+	} else {
+		c.states[id] = state
+	}
+	return true
+}
+
+func (c *CheckSet) registryChanged(event RegistryEvent) {
+	var changed []widget.TreeNodeID
+	c.mux.Lock()
+	switch event.Kind {
+	case NodeAdded:
+		if c.states[event.ParentID] == Checked {
+			changed = c.setSubtree(event.ID, Checked, changed)
+		}
+	case NodeLoaded:
+		if c.states[event.ID] == Checked {
+			changed = c.setSubtree(event.ID, Checked, changed)
+		}
+	case NodeRemoved:
+		// Descendants are already gone from the registry, so anything no longer registered is dropped.
+		for id := range c.states {
+			if c.registry.Node(id) == nil {
+				delete(c.states, id)
+			}
+		}
+		changed = c.updateAncestors(event.ParentID, changed)
+	case NodeMoved:
+		changed = c.updateAncestors(event.PreviousParentID, changed)
+		changed = c.updateAncestors(event.ParentID, changed)
+	}
+	c.mux.Unlock()
+	c.changed(changed)
+}
+
+func (c *CheckSet) changed(changed []widget.TreeNodeID) {
+	if len(changed) > 0 && c.OnChanged != nil {
+		c.OnChanged(changed)
+	}
+}
@@ -0,0 +1,123 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+// buildCheckTree registers the models from buildModels and returns the registry and IDs by name.
+func buildCheckTree(t *testing.T) (*TreeModelRegistry, map[string]widget.TreeNodeID) {
+	reg := NewTreeModelRegistry()
+	if _, err := reg.AddChild(ModelRoot, buildModels(t)); err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]widget.TreeNodeID{}
+	reg.Walk(func(_ widget.TreeNodeID, _ TreeModel, nodeID widget.TreeNodeID, node TreeModel) {
+		ids[node.DisplayString()] = nodeID
+	})
+	return reg, ids
+}
+
+func TestCheckSet_SetChecked(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	checks := NewCheckSet(reg)
+
+	var reported []widget.TreeNodeID
+	checks.OnChanged = func(changed []widget.TreeNodeID) {
+		reported = append(reported, changed...)
+	}
+
+	changed := checks.SetChecked(ids["a1"], true)
+	assert.ElementsMatch([]widget.TreeNodeID{ids["a1"], ids["a"], ids["root"]}, changed)
+	assert.ElementsMatch(changed, reported, "Changes should be reported to OnChanged")
+	assert.Equal(Checked, checks.State(ids["a1"]))
+	assert.Equal(Indeterminate, checks.State(ids["a"]))
+	assert.Equal(Indeterminate, checks.State(ids["root"]))
+	assert.Equal(Unchecked, checks.State(ids["b"]))
+
+	checks.SetChecked(ids["a2"], true)
+	assert.Equal(Checked, checks.State(ids["a"]), "All children are checked")
+	assert.Equal(Indeterminate, checks.State(ids["root"]))
+
+	checks.SetChecked(ids["root"], true)
+	for name, id := range ids {
+		assert.Equal(Checked, checks.State(id), "%s should be checked", name)
+	}
+	assert.Equal([]widget.TreeNodeID{ids["root"], ids["a"], ids["a1"], ids["a2"], ids["b"]}, checks.CheckedIDs())
+
+	checks.SetChecked(ids["a"], false)
+	assert.Equal(Unchecked, checks.State(ids["a1"]), "Unchecking should propagate down")
+	assert.Equal(Indeterminate, checks.State(ids["root"]))
+	assert.Equal([]widget.TreeNodeID{ids["b"]}, checks.CheckedIDs())
+
+	assert.Nil(checks.SetChecked("does not exist", true))
+	assert.Nil(checks.SetChecked(ModelRoot, true))
+}
+
+func TestCheckSet_RegistryChanges(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	checks := NewCheckSet(reg)
+
+	checks.SetChecked(ids["a"], true)
+	a3, err := reg.AddChild(ids["a"], &ModelData{Data: "a3"})
+	assert.NoError(err)
+	assert.Equal(Checked, checks.State(a3), "Children of checked nodes should be checked")
+
+	b1, err := reg.AddChild(ids["b"], &ModelData{Data: "b1"})
+	assert.NoError(err)
+	assert.Equal(Unchecked, checks.State(b1))
+
+	reg.RemoveChild(ids["b"])
+	assert.Equal(Checked, checks.State(ids["root"]), "Removing the only unchecked child should check the parent")
+
+	reg.RemoveChild(ids["a"])
+	assert.Equal(Checked, checks.State(ids["root"]), "A parent without children keeps its state")
+	assert.Equal([]widget.TreeNodeID{ids["root"]}, checks.CheckedIDs())
+	assert.Len(checks.states, 1, "Removed nodes should be forgotten")
+}
+
+func TestCheckSet_RemoveLastChild(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	checks := NewCheckSet(reg)
+	x, err := reg.AddChild(ids["a1"], &ModelData{Data: "x"})
+	assert.NoError(err)
+	_, err = reg.AddChild(ids["a1"], &ModelData{Data: "y"})
+	assert.NoError(err)
+	checks.SetChecked(x, true)
+	reg.RemoveChild(ids["a2"])
+	assert.Equal(Indeterminate, checks.State(ids["a"]), "a's only child is indeterminate")
+
+	reg.RemoveChild(ids["a1"])
+	assert.Equal(Unchecked, checks.State(ids["a"]), "A parent left without children shouldn't stay indeterminate")
+	assert.Equal(Unchecked, checks.State(ids["root"]))
+}
+
+func TestCheckSet_Close(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	checks := NewCheckSet(reg)
+	checks.SetChecked(ids["a"], true)
+
+	checks.Close()
+	a3, err := reg.AddChild(ids["a"], &ModelData{Data: "a3"})
+	assert.NoError(err)
+	assert.Equal(Unchecked, checks.State(a3), "A closed CheckSet shouldn't follow the registry")
+}
+
+func TestCheckSet_MovedNode(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	checks := NewCheckSet(reg)
+
+	checks.SetChecked(ids["a1"], true)
+	assert.Equal(Indeterminate, checks.State(ids["a"]))
+	assert.NoError(reg.MoveChild(ids["a2"], ids["b"], 0))
+	assert.Equal(Checked, checks.State(ids["a"]), "The old parent only has checked children left")
+	assert.Equal(Unchecked, checks.State(ids["b"]), "The new parent only has an unchecked child")
+	assert.Equal(Indeterminate, checks.State(ids["root"]))
+}
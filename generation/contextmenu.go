@@ -0,0 +1,33 @@
+package generation
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// ContextMenuTreeModel may be implemented by a TreeModel to add items to the context menu of its node.
+type ContextMenuTreeModel interface {
+	ContextMenuItems() []*fyne.MenuItem // ContextMenuItems returns the items to add. Return nil to add nothing.
+}
+
+// ContextMenu builds the context menu for model, starting with the items in base, which may be nil. Items from a model
+// implementing ContextMenuTreeModel are added after a separator. Nil is returned if there are no items to show.
+func ContextMenu(model TreeModel, base *fyne.Menu) *fyne.Menu {
+	var label string
+	var items []*fyne.MenuItem
+	if base != nil {
+		label = base.Label
+		items = append(items, base.Items...)
+	}
+	if contributor, ok := model.(ContextMenuTreeModel); ok {
+		if modelItems := contributor.ContextMenuItems(); len(modelItems) > 0 {
+			if len(items) > 0 {
+				items = append(items, fyne.NewMenuItemSeparator())
+			}
+			items = append(items, modelItems...)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return fyne.NewMenu(label, items...)
+}
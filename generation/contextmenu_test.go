@@ -0,0 +1,74 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	testify "github.com/stretchr/testify/require"
+)
+
+type menuModel struct {
+	ModelData
+	items []*fyne.MenuItem
+}
+
+func (m *menuModel) ContextMenuItems() []*fyne.MenuItem {
+	return m.items
+}
+
+func TestContextMenu(t *testing.T) {
+	open := fyne.NewMenuItem("Open", nil)
+	rename := fyne.NewMenuItem("Rename", nil)
+	base := fyne.NewMenu("Node", open)
+
+	tests := map[string]struct {
+		model    TreeModel
+		base     *fyne.Menu
+		expected []string
+	}{
+		"No items": {
+			model: &ModelData{},
+		},
+		"Base only": {
+			model:    &ModelData{},
+			base:     base,
+			expected: []string{"Open"},
+		},
+		"Model only": {
+			model:    &menuModel{items: []*fyne.MenuItem{rename}},
+			expected: []string{"Rename"},
+		},
+		"Both": {
+			model:    &menuModel{items: []*fyne.MenuItem{rename}},
+			base:     base,
+			expected: []string{"Open", "", "Rename"},
+		},
+		"Model without items": {
+			model:    &menuModel{},
+			base:     base,
+			expected: []string{"Open"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := testify.New(t)
+			menu := ContextMenu(tc.model, tc.base)
+			if tc.expected == nil {
+				assert.Nil(menu)
+				return
+			}
+			var labels []string
+			for _, item := range menu.Items {
+				labels = append(labels, item.Label)
+			}
+			assert.Equal(tc.expected, labels)
+			if tc.base != nil {
+				assert.Equal("Node", menu.Label)
+			}
+		})
+	}
+	assert := testify.New(t)
+	ContextMenu(&menuModel{items: []*fyne.MenuItem{rename}}, base)
+	assert.Len(base.Items, 1, "The base menu should not be changed")
+}
@@ -0,0 +1,98 @@
+package generation
+
+import (
+	"fyne.io/fyne/v2/widget"
+)
+
+// DropPosition is where a dragged node will be placed relative to the node it's dropped on.
+type DropPosition int
+
+const (
+	DropNone   DropPosition = iota // DropNone means there's no valid place to drop.
+	DropBefore                     // DropBefore places the dragged node before the target, under the same parent.
+	DropAfter                      // DropAfter places the dragged node after the target, under the same parent.
+	DropInto                       // DropInto makes the dragged node the last child of the target.
+)
+
+// DropTarget is a node and the position relative to it that a dragged node would be dropped.
+type DropTarget struct {
+	ID       widget.TreeNodeID
+	Position DropPosition
+}
+
+// DropPositionAt computes where a node would be dropped, given the pointer's y position within a target node of the
+// given height. The top and bottom quarters of the target drop before and after it, and the middle drops into it.
+func DropPositionAt(y float32, height float32) DropPosition {
+	switch {
+	case height <= 0 || y < 0 || y > height:
+		return DropNone
+	case y < height/4:
+		return DropBefore
+	case y > height*3/4:
+		return DropAfter
+	default:
+		return DropInto
+	}
+}
+
+// CanDrop checks whether nodeID could be dropped on target, without changing anything.
+func (r *TreeModelRegistry) CanDrop(nodeID widget.TreeNodeID, target DropTarget) error {
+	parentID, _, err := r.dropLocation(nodeID, target)
+	if err != nil {
+		return err
+	}
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	for id := parentID; id != ModelRoot; id = r.parentMap[id] {
+		if id == nodeID {
+			return ErrInvalidMove
+		}
+	}
+	if parent := r.idMap[parentID]; parent != nil && r.parentMap[nodeID] != parentID {
+		return CheckChildPolicy(parent, r.idMap[nodeID])
+	}
+	return nil
+}
+
+// Drop moves nodeID to the drop target with MoveChild.
+func (r *TreeModelRegistry) Drop(nodeID widget.TreeNodeID, target DropTarget) error {
+	parentID, index, err := r.dropLocation(nodeID, target)
+	if err != nil {
+		return err
+	}
+	return r.MoveChild(nodeID, parentID, index)
+}
+
+// dropLocation translates a drop target to the parent and index expected by MoveChild.
+func (r *TreeModelRegistry) dropLocation(nodeID widget.TreeNodeID, target DropTarget) (widget.TreeNodeID, int, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	if _, ok := r.parentMap[nodeID]; !ok {
+		return "", 0, ErrNoSuchNode
+	}
+	targetParentID, ok := r.parentMap[target.ID]
+	if !ok {
+		return "", 0, ErrNoSuchParent
+	}
+	if target.ID == nodeID {
+		return "", 0, ErrInvalidMove
+	}
+
+	switch target.Position {
+	case DropInto:
+		return target.ID, -1, nil
+	case DropBefore, DropAfter:
+		siblings := r.childMap[targetParentID]
+		index := indexOfID(siblings, target.ID)
+		if target.Position == DropAfter {
+			index++
+		}
+		// MoveChild indexes siblings after the node is removed, so account for it being earlier in the same list.
+		if current := indexOfID(siblings, nodeID); current >= 0 && current < index {
+			index--
+		}
+		return targetParentID, index, nil
+	default:
+		return "", 0, ErrInvalidMove
+	}
+}
@@ -0,0 +1,119 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestDropPositionAt(t *testing.T) {
+	tests := map[string]struct {
+		y        float32
+		height   float32
+		expected DropPosition
+	}{
+		"Top quarter":    {y: 2, height: 20, expected: DropBefore},
+		"Middle":         {y: 10, height: 20, expected: DropInto},
+		"Bottom quarter": {y: 18, height: 20, expected: DropAfter},
+		"Above":          {y: -1, height: 20, expected: DropNone},
+		"Below":          {y: 21, height: 20, expected: DropNone},
+		"No height":      {y: 0, height: 0, expected: DropNone},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			testify.Equal(t, tc.expected, DropPositionAt(tc.y, tc.height))
+		})
+	}
+}
+
+// displayed returns the display strings of a node's children, according to both the registry and the model.
+func displayed(reg *TreeModelRegistry, id widget.TreeNodeID) (registered []string, modeled []string) {
+	for _, cid := range reg.Children(id) {
+		registered = append(registered, reg.Node(cid).DisplayString())
+	}
+	if model := reg.Node(id); model != nil {
+		for _, c := range model.Children() {
+			modeled = append(modeled, c.DisplayString())
+		}
+	}
+	return registered, modeled
+}
+
+func TestTreeModelRegistry_Drop(t *testing.T) {
+	tests := map[string]struct {
+		node     string
+		target   DropTarget
+		parent   string
+		expected []string
+	}{
+		"Before sibling": {
+			node:     "a2",
+			target:   DropTarget{ID: "a1", Position: DropBefore},
+			parent:   "a",
+			expected: []string{"a2", "a1"},
+		},
+		"After later sibling": {
+			node:     "a1",
+			target:   DropTarget{ID: "a2", Position: DropAfter},
+			parent:   "a",
+			expected: []string{"a2", "a1"},
+		},
+		"After earlier sibling": {
+			node:     "a1",
+			target:   DropTarget{ID: "a1", Position: DropAfter},
+			parent:   "a",
+			expected: nil,
+		},
+		"Into other branch": {
+			node:     "a1",
+			target:   DropTarget{ID: "b", Position: DropInto},
+			parent:   "b",
+			expected: []string{"a1"},
+		},
+		"Before node in other branch": {
+			node:     "b",
+			target:   DropTarget{ID: "a2", Position: DropBefore},
+			parent:   "a",
+			expected: []string{"a1", "b", "a2"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := testify.New(t)
+			reg, ids := buildCheckTree(t)
+			target := DropTarget{ID: ids[tc.target.ID], Position: tc.target.Position}
+			err := reg.Drop(ids[tc.node], target)
+			if tc.expected == nil {
+				assert.True(errors.Is(err, ErrInvalidMove), "Dropping a node on itself is invalid")
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(ids[tc.parent], reg.Parent(ids[tc.node]))
+			registered, modeled := displayed(reg, ids[tc.parent])
+			assert.Equal(tc.expected, registered)
+			assert.Equal(tc.expected, modeled, "The move should be propagated to the models")
+		})
+	}
+}
+
+func TestTreeModelRegistry_CanDrop(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+
+	assert.NoError(reg.CanDrop(ids["a1"], DropTarget{ID: ids["b"], Position: DropInto}))
+	assert.True(errors.Is(reg.CanDrop(ids["a"], DropTarget{ID: ids["a1"], Position: DropInto}), ErrInvalidMove), "A node can't be dropped into its descendants")
+	assert.True(errors.Is(reg.CanDrop(ids["root"], DropTarget{ID: ids["a1"], Position: DropAfter}), ErrInvalidMove))
+	assert.True(errors.Is(reg.CanDrop("nope", DropTarget{ID: ids["a1"], Position: DropAfter}), ErrNoSuchNode))
+
+	file := newPolicyFile()
+	fileID, err := reg.AddChild(ModelRoot, file)
+	assert.NoError(err)
+	var rejected *ErrRejected
+	assert.True(errors.As(reg.CanDrop(ids["b"], DropTarget{ID: fileID, Position: DropInto}), &rejected))
+	assert.True(errors.As(reg.Drop(ids["b"], DropTarget{ID: fileID, Position: DropInto}), &rejected))
+	assert.Equal(ids["root"], reg.Parent(ids["b"]), "Rejected drops should not move anything")
+}
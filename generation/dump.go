@@ -0,0 +1,159 @@
+package generation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/pkg/errors"
+)
+
+// DumpFormat selects how TreeModelRegistry.Dump writes the registry.
+type DumpFormat int
+
+const (
+	DumpASCII DumpFormat = iota // DumpASCII draws the registered tree with box-drawing characters, followed by any problems found.
+	DumpDOT                     // DumpDOT writes a Graphviz digraph with an edge for every registered child, and problems highlighted in red.
+)
+
+// Dump writes the registry's structure to w in format, for debugging. Nodes are shown with their display string and
+// ID, and the registry is checked for problems like orphaned nodes, parent links to unregistered nodes, and models
+// whose children don't match the children registered for them.
+func (r *TreeModelRegistry) Dump(w io.Writer, format DumpFormat) error {
+	r.mux.RLock()
+	var buf strings.Builder
+	switch format {
+	case DumpASCII:
+		r.dumpASCII(&buf)
+	case DumpDOT:
+		r.dumpDOT(&buf)
+	default:
+		r.mux.RUnlock()
+		return errors.Errorf("unknown dump format %d", format)
+	}
+	r.mux.RUnlock()
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func (r *TreeModelRegistry) dumpASCII(buf *strings.Builder) {
+	buf.WriteString("(root)\n")
+	seen := map[widget.TreeNodeID]bool{}
+	var draw func(parentID widget.TreeNodeID, prefix string)
+	draw = func(parentID widget.TreeNodeID, prefix string) {
+		children := r.childMap[parentID]
+		for i, id := range children {
+			branch, indent := "├── ", "│   "
+			if i == len(children)-1 {
+				branch, indent = "└── ", "    "
+			}
+			buf.WriteString(prefix + branch + r.dumpLabel(id))
+			if seen[id] {
+				buf.WriteString(" (repeated)\n")
+				continue
+			}
+			seen[id] = true
+			buf.WriteString("\n")
+			draw(id, prefix+indent)
+		}
+	}
+	draw(ModelRoot, "")
+
+	problems := r.problems()
+	if len(problems) == 0 {
+		buf.WriteString("\nNo problems found.\n")
+		return
+	}
+	fmt.Fprintf(buf, "\n%d problems found:\n", len(problems))
+	for _, problem := range problems {
+		fmt.Fprintf(buf, "  ! %s\n", problem.message)
+	}
+}
+
+func (r *TreeModelRegistry) dumpDOT(buf *strings.Builder) {
+	problems := r.problems()
+	flagged := map[widget.TreeNodeID]bool{}
+	buf.WriteString("digraph registry {\n")
+	for _, problem := range problems {
+		flagged[problem.id] = true
+		fmt.Fprintf(buf, "\t// problem: %s\n", strings.ReplaceAll(problem.message, "\n", " "))
+	}
+	buf.WriteString("\tnode [shape=box];\n")
+
+	// Graphviz node names are assigned as IDs are found, since IDs can be any string, including the root's empty ID.
+	names := map[widget.TreeNodeID]string{}
+	name := func(id widget.TreeNodeID) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		n := fmt.Sprintf("n%d", len(names))
+		names[id] = n
+		label := "(root)"
+		if id != ModelRoot {
+			label = r.dumpLabel(id)
+		}
+		attrs := "label=" + dotQuote(label)
+		if flagged[id] {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(buf, "\t%s [%s];\n", n, attrs)
+		return n
+	}
+	name(ModelRoot)
+	for _, id := range sortedIDs(r.idMap) {
+		name(id)
+	}
+	for _, parentID := range sortedIDs(r.childMap) {
+		for _, id := range r.childMap[parentID] {
+			attrs := ""
+			if r.parentMap[id] != parentID {
+				attrs = " [color=red]"
+			}
+			fmt.Fprintf(buf, "\t%s -> %s%s;\n", name(parentID), name(id), attrs)
+		}
+	}
+	// Parent links that aren't matched by a child link are drawn back to the parent.
+	for _, id := range sortedIDs(r.parentMap) {
+		parentID := r.parentMap[id]
+		if !containsID(r.childMap[parentID], id) {
+			fmt.Fprintf(buf, "\t%s -> %s [color=red, style=dashed, label=\"parent\"];\n", name(id), name(parentID))
+		}
+	}
+	buf.WriteString("}\n")
+}
+
+// dumpLabel is the display string and ID of a node, noting if it isn't registered or loaded.
+func (r *TreeModelRegistry) dumpLabel(id widget.TreeNodeID) string {
+	model, ok := r.idMap[id]
+	var label string
+	switch {
+	case !ok:
+		label = "(unregistered)"
+	case isNilModel(model):
+		label = "(nil)"
+	default:
+		label = model.DisplayString()
+	}
+	label = fmt.Sprintf("%s [%s]", label, id)
+	if r.unloaded[id] {
+		label += " (not loaded)"
+	}
+	return label
+}
+
+// sortedIDs returns the keys of m in order, so dumps and problems are listed the same way each time.
+func sortedIDs[V any](m map[widget.TreeNodeID]V) []widget.TreeNodeID {
+	ids := make([]widget.TreeNodeID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// dotQuote quotes s as a Graphviz string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
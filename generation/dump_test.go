@@ -0,0 +1,93 @@
+package generation
+
+import (
+	"bytes"
+	"testing"
+
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestTreeModelRegistry_DumpASCII(t *testing.T) {
+	assert := testify.New(t)
+	reg, _ := buildTopLevelRegistry(t)
+	_, err := reg.AddChild("b", &lazyModel{ModelData: ModelData{Data: "lazy"}})
+	assert.NoError(err)
+	var buf bytes.Buffer
+	assert.NoError(reg.Dump(&buf, DumpASCII))
+	assert.Equal(`(root)
+├── a [a]
+│   ├── a1 [a/a1]
+│   └── a2 [a/a2]
+└── b [b]
+    └── lazy [b/lazy] (not loaded)
+
+No problems found.
+`, buf.String())
+	assert.Error(reg.Dump(&buf, DumpFormat(-1)))
+}
+
+func TestTreeModelRegistry_DumpDOT(t *testing.T) {
+	assert := testify.New(t)
+	reg, _ := buildTopLevelRegistry(t)
+	reg.idMap["b"] = &ModelData{Data: `say "hi"`}
+	var buf bytes.Buffer
+	assert.NoError(reg.Dump(&buf, DumpDOT))
+	assert.Equal(`digraph registry {
+	node [shape=box];
+	n0 [label="(root)"];
+	n1 [label="a [a]"];
+	n2 [label="a1 [a/a1]"];
+	n3 [label="a2 [a/a2]"];
+	n4 [label="say \"hi\" [b]"];
+	n0 -> n1;
+	n0 -> n4;
+	n1 -> n2;
+	n1 -> n3;
+}
+`, buf.String())
+}
+
+func TestTreeModelRegistry_DumpProblems(t *testing.T) {
+	assert := testify.New(t)
+	reg, a := buildTopLevelRegistry(t)
+	reg.idMap["orphan"] = &ModelData{Data: "orphan"}
+	reg.parentMap["ghost"] = "a"
+	reg.parentMap["a/a2"] = "b"
+	reg.childMap["b"] = []string{}
+	assert.NoError(a.AddChild(&ModelData{Data: "unregistered"}))
+
+	var buf bytes.Buffer
+	assert.NoError(reg.Dump(&buf, DumpASCII))
+	assert.Equal(`(root)
+├── a [a]
+│   ├── a1 [a/a1]
+│   └── a2 [a/a2]
+└── b [b]
+
+6 problems found:
+  ! the parent of "a/a2", "b", doesn't list it as a child
+  ! orphan: "orphan" is registered without a parent
+  ! dangling parent link: "ghost" has parent "a", but isn't registered
+  ! "a/a2" is listed under "a", but its parent is "b"
+  ! "b" has an empty child list
+  ! the model of "a" has 3 children, but 2 are registered
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(reg.Dump(&buf, DumpDOT))
+	dot := buf.String()
+	assert.Contains(dot, "\t// problem: orphan: \"orphan\" is registered without a parent\n")
+	assert.Contains(dot, `n5 [label="orphan [orphan]", color=red];`)
+	assert.Contains(dot, "n1 -> n3 [color=red];", "Children whose parent link disagrees should be highlighted")
+	assert.Contains(dot, `n3 -> n4 [color=red, style=dashed, label="parent"];`)
+	assert.Contains(dot, `n6 [label="(unregistered) [ghost]", color=red];`)
+
+	cycle, _ := buildTopLevelRegistry(t)
+	cycle.idMap["x"], cycle.idMap["y"] = &ModelData{Data: "x"}, &ModelData{Data: "y"}
+	cycle.parentMap["x"], cycle.parentMap["y"] = "y", "x"
+	cycle.childMap["x"], cycle.childMap["y"] = []string{"y"}, []string{"x"}
+	buf.Reset()
+	assert.NoError(cycle.Dump(&buf, DumpASCII))
+	assert.Contains(buf.String(), `! "x" can't be reached from the root`)
+	assert.Contains(buf.String(), `! "y" can't be reached from the root`)
+}
@@ -0,0 +1,377 @@
+package generation
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"github.com/pkg/errors"
+)
+
+var _ LazyTreeModel = (*FSTreeModel)(nil)
+var _ RenamableTreeModel = (*FSTreeModel)(nil)
+var _ ChildPolicy = (*FSTreeModel)(nil)
+
+var (
+	ErrReadOnly    = errors.New("the file system is read only")
+	ErrInvalidName = errors.New("invalid file name")
+	ErrNotDir      = errors.New("not a directory")
+)
+
+// WritableFS is an fs.FS that can also be changed, which lets an FSTreeModel rename, remove and create files. Paths
+// are slash-separated and relative to the root of the file system, like fs.FS paths.
+type WritableFS interface {
+	fs.FS
+	Rename(oldPath, newPath string) error
+	RemoveAll(path string) error
+	Mkdir(path string, perm fs.FileMode) error
+}
+
+// DirFS returns a WritableFS for the files in dir, like os.DirFS.
+func DirFS(dir string) WritableFS {
+	return &dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (d *dirFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(d.dir, filepath.FromSlash(name)), nil
+}
+
+func (d *dirFS) Rename(oldPath, newPath string) error {
+	from, err := d.join("rename", oldPath)
+	if err != nil {
+		return err
+	}
+	to, err := d.join("rename", newPath)
+	if err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+func (d *dirFS) RemoveAll(name string) error {
+	full, err := d.join("remove", name)
+	if err != nil {
+		return err
+	}
+	if name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrPermission}
+	}
+	return os.RemoveAll(full)
+}
+
+func (d *dirFS) Mkdir(name string, perm fs.FileMode) error {
+	full, err := d.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.Mkdir(full, perm)
+}
+
+// FSOption configures the models created by NewFSTreeModel.
+type FSOption func(config *fsConfig)
+
+type fsConfig struct {
+	hideHidden bool
+	rootName   string
+}
+
+// WithoutHiddenFiles leaves out files and directories whose names start with a dot.
+func WithoutHiddenFiles() FSOption {
+	return func(config *fsConfig) {
+		config.hideHidden = true
+	}
+}
+
+// WithRootName displays the root as name, instead of the base name of its path. The root of a DirFS is named after
+// its directory by default.
+func WithRootName(name string) FSOption {
+	return func(config *fsConfig) {
+		config.rootName = name
+	}
+}
+
+// FSTreeModel is a LazyTreeModel for a file or directory in an fs.FS, which reads a directory's entries when its node
+// is first opened. Directories are listed before files, and both are sorted by name, ignoring case. If the file system
+// is a WritableFS, like the one returned by DirFS, nodes can be renamed, removed and have directories created in them.
+type FSTreeModel struct {
+	BaseTreeModel
+
+	fsys   fs.FS
+	config *fsConfig
+	parent *FSTreeModel
+	dir    bool
+
+	mux     sync.RWMutex
+	name    string
+	loaded  bool
+	loadErr error
+}
+
+// NewFSTreeModel creates a model for the file or directory at root in fsys. Its descendants share the same options.
+func NewFSTreeModel(fsys fs.FS, root string, options ...FSOption) (*FSTreeModel, error) {
+	info, err := fs.Stat(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+	config := &fsConfig{}
+	for _, option := range options {
+		option(config)
+	}
+	if d, ok := fsys.(*dirFS); ok && config.rootName == "" && path.Clean(root) == "." {
+		config.rootName = filepath.Base(d.dir)
+	}
+	m := &FSTreeModel{
+		fsys:   fsys,
+		config: config,
+		dir:    info.IsDir(),
+		name:   root,
+	}
+	return m, nil
+}
+
+// Path returns the model's slash-separated path within its file system.
+func (m *FSTreeModel) Path() string {
+	m.mux.RLock()
+	name := m.name
+	m.mux.RUnlock()
+	if m.parent == nil {
+		return name
+	}
+	return path.Join(m.parent.Path(), name)
+}
+
+// Name returns the base name of the file or directory.
+func (m *FSTreeModel) Name() string {
+	return path.Base(m.Path())
+}
+
+// IsDir reports whether the model is a directory.
+func (m *FSTreeModel) IsDir() bool {
+	return m.dir
+}
+
+// FS returns the file system the model reads from.
+func (m *FSTreeModel) FS() fs.FS {
+	return m.fsys
+}
+
+// Writable reports whether the model's file system is a WritableFS.
+func (m *FSTreeModel) Writable() bool {
+	_, ok := m.fsys.(WritableFS)
+	return ok
+}
+
+// Err returns the error from reading the directory's entries, if any.
+func (m *FSTreeModel) Err() error {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.loadErr
+}
+
+func (m *FSTreeModel) DisplayIcon() fyne.Resource {
+	if m.dir {
+		return theme.FolderIcon()
+	}
+	return fileIcon(m.Name())
+}
+
+func (m *FSTreeModel) DisplayString() string {
+	if m.parent == nil && m.config.rootName != "" {
+		return m.config.rootName
+	}
+	return m.Name()
+}
+
+func (m *FSTreeModel) MayHaveChildren() bool {
+	return m.dir
+}
+
+// Children reads the directory's entries the first time it's called.
+func (m *FSTreeModel) Children() []TreeModel {
+	m.load()
+	return m.BaseTreeModel.Children()
+}
+
+func (m *FSTreeModel) AddChild(child TreeModel) error {
+	m.load()
+	return m.BaseTreeModel.AddChild(child)
+}
+
+func (m *FSTreeModel) AddChildAt(index int, child TreeModel) error {
+	m.load()
+	return m.BaseTreeModel.AddChildAt(index, child)
+}
+
+func (m *FSTreeModel) RemoveChild() TreeModel {
+	m.load()
+	return m.BaseTreeModel.RemoveChild()
+}
+
+func (m *FSTreeModel) RemoveChildAt(index int) TreeModel {
+	m.load()
+	return m.BaseTreeModel.RemoveChildAt(index)
+}
+
+// CanAccept only accepts models for entries of this directory, like those created by Mkdir, since moving a model
+// between directories doesn't move the file.
+func (m *FSTreeModel) CanAccept(child TreeModel) bool {
+	fsChild, ok := child.(*FSTreeModel)
+	return ok && m.dir && fsChild.parent == m
+}
+
+func (m *FSTreeModel) MaxChildren() int {
+	if m.dir {
+		return Unlimited
+	}
+	return 0
+}
+
+func (m *FSTreeModel) AllowedTypes() []TreeModel {
+	return []TreeModel{(*FSTreeModel)(nil)}
+}
+
+// SetDisplayString renames the file or directory. A name that's already used by another entry in the same directory is
+// refused with an error wrapping fs.ErrExist, rather than replacing that entry.
+func (m *FSTreeModel) SetDisplayString(name string) error {
+	wfs, ok := m.fsys.(WritableFS)
+	if !ok {
+		return ErrReadOnly
+	}
+	if m.parent == nil {
+		return errors.Wrap(ErrInvalidName, "the root can't be renamed")
+	}
+	if err := validName(name); err != nil {
+		return err
+	}
+	oldPath := m.Path()
+	newPath := path.Join(path.Dir(oldPath), name)
+	if newPath == oldPath {
+		return nil
+	}
+	if existing, err := fs.Stat(wfs, newPath); err == nil {
+		// Case-insensitive file systems find the entry being renamed when only the case of its name changes.
+		if current, err := fs.Stat(wfs, oldPath); err != nil || !os.SameFile(existing, current) {
+			return errors.Wrapf(fs.ErrExist, "'%s'", name)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := wfs.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+	m.mux.Lock()
+	m.name = name
+	m.mux.Unlock()
+	m.NotifyChanged()
+	return nil
+}
+
+// Remove deletes the file, or the directory and everything in it. The model's node should then be removed with the
+// registry's RemoveChild.
+func (m *FSTreeModel) Remove() error {
+	wfs, ok := m.fsys.(WritableFS)
+	if !ok {
+		return ErrReadOnly
+	}
+	return wfs.RemoveAll(m.Path())
+}
+
+// Mkdir creates a directory called name in this directory, and returns its model to be added to this model's node with
+// the registry's AddChild.
+func (m *FSTreeModel) Mkdir(name string) (*FSTreeModel, error) {
+	wfs, ok := m.fsys.(WritableFS)
+	if !ok {
+		return nil, ErrReadOnly
+	}
+	if !m.dir {
+		return nil, ErrNotDir
+	}
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+	// The existing entries are read first, so the new directory is only added by AddChild.
+	m.load()
+	if err := wfs.Mkdir(path.Join(m.Path(), name), 0755); err != nil {
+		return nil, err
+	}
+	return m.child(name, true), nil
+}
+
+func (m *FSTreeModel) child(name string, dir bool) *FSTreeModel {
+	return &FSTreeModel{
+		fsys:   m.fsys,
+		config: m.config,
+		parent: m,
+		dir:    dir,
+		name:   name,
+	}
+}
+
+func (m *FSTreeModel) load() {
+	if !m.dir {
+		return
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.loaded {
+		return
+	}
+	m.loaded = true
+	name := m.name
+	if m.parent != nil {
+		name = path.Join(m.parent.Path(), name)
+	}
+	entries, err := fs.ReadDir(m.fsys, name)
+	m.loadErr = err
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return strings.ToLower(entries[i].Name()) < strings.ToLower(entries[j].Name())
+	})
+	for _, entry := range entries {
+		if m.config.hideHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		_ = m.BaseTreeModel.AddChild(m.child(entry.Name(), entry.IsDir()))
+	}
+}
+
+func validName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return errors.Wrapf(ErrInvalidName, "'%s'", name)
+	}
+	return nil
+}
+
+// fileIcon returns the theme icon for the kind of file name is, going by its extension.
+func fileIcon(name string) fyne.Resource {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".txt", ".md", ".csv", ".log", ".json", ".xml", ".yaml", ".yml", ".toml", ".go", ".html", ".css":
+		return theme.FileTextIcon()
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".bmp", ".webp":
+		return theme.FileImageIcon()
+	case ".mp3", ".wav", ".ogg", ".flac":
+		return theme.FileAudioIcon()
+	case ".mp4", ".mkv", ".mov", ".avi", ".webm":
+		return theme.FileVideoIcon()
+	case ".exe", ".zip", ".tar", ".gz", ".pdf", ".jar":
+		return theme.FileApplicationIcon()
+	default:
+		return theme.FileIcon()
+	}
+}
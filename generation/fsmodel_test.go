@@ -0,0 +1,175 @@
+package generation
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"project/README.md":        {Data: []byte("readme")},
+		"project/logo.PNG":         {Data: []byte("png")},
+		"project/.gitignore":       {Data: []byte("*.tmp")},
+		"project/src/main.go":      {Data: []byte("package main")},
+		"project/assets/font.ttf":  {Data: []byte("font")},
+		"project/.git/HEAD":        {Data: []byte("ref")},
+		"project/Build/output.bin": {Data: []byte("bin")},
+	}
+}
+
+func displayStrings(models []TreeModel) []string {
+	names := make([]string, len(models))
+	for i, model := range models {
+		names[i] = model.DisplayString()
+	}
+	return names
+}
+
+func TestFSTreeModel_Children(t *testing.T) {
+	assert := testify.New(t)
+	root, err := NewFSTreeModel(testFS(), "project")
+	assert.NoError(err)
+	assert.Equal("project", root.DisplayString())
+	assert.True(root.IsDir())
+	assert.True(root.MayHaveChildren())
+	assert.False(root.Writable())
+
+	children := root.Children()
+	assert.Equal([]string{".git", "assets", "Build", "src", ".gitignore", "logo.PNG", "README.md"}, displayStrings(children), "Directories should be sorted before files, ignoring case")
+	assert.NoError(root.Err())
+
+	src := children[3].(*FSTreeModel)
+	assert.Equal("project/src", src.Path())
+	assert.Equal(theme.FolderIcon(), src.DisplayIcon())
+	main := src.Children()[0].(*FSTreeModel)
+	assert.Equal("project/src/main.go", main.Path())
+	assert.False(main.MayHaveChildren())
+	assert.Empty(main.Children())
+	assert.Equal(theme.FileTextIcon(), main.DisplayIcon())
+	assert.Equal(theme.FileImageIcon(), children[5].DisplayIcon(), "Extensions should be matched ignoring case")
+	assert.Equal(theme.FileIcon(), children[0].Children()[0].DisplayIcon())
+
+	hidden, err := NewFSTreeModel(testFS(), "project", WithoutHiddenFiles(), WithRootName("Project"))
+	assert.NoError(err)
+	assert.Equal("Project", hidden.DisplayString())
+	assert.Equal([]string{"assets", "Build", "src", "logo.PNG", "README.md"}, displayStrings(hidden.Children()))
+
+	_, err = NewFSTreeModel(testFS(), "missing")
+	assert.ErrorIs(err, fs.ErrNotExist)
+}
+
+func TestFSTreeModel_Lazy(t *testing.T) {
+	assert := testify.New(t)
+	root, err := NewFSTreeModel(testFS(), "project")
+	assert.NoError(err)
+	tree := NewTree(WithIDStrategy(PathStrategy), WithRoots(root))
+	rootID := tree.Children(ModelRoot)[0]
+	assert.False(tree.IsLoaded(rootID))
+	assert.True(tree.IsBranch(rootID))
+
+	tree.OpenBranch(rootID)
+	assert.Len(tree.Children(rootID), 7)
+	srcID := tree.Children(rootID)[3]
+	assert.False(tree.IsLoaded(srcID), "Only the opened directory should be read")
+	assert.Equal([]string{"main.go"}, displayStrings(tree.Node(srcID).(*FSTreeModel).Children()))
+
+	tree.ExpandAll(-1)
+	state := tree.SaveViewState()
+	assert.Contains(state.Open, srcID, "Expanding should load and open nested directories")
+	root2, err := NewFSTreeModel(testFS(), "project")
+	assert.NoError(err)
+	restored := NewTree(WithIDStrategy(PathStrategy), WithRoots(root2))
+	restored.RestoreViewState(state)
+	assert.Equal(tree.visibleIDs(), restored.visibleIDs(), "Restoring should load the directories it opens")
+
+	readmeID := tree.Children(rootID)[6]
+	assert.Error(tree.MoveChild(readmeID, srcID, 0), "Files shouldn't be moved between directories")
+	assert.Error(tree.MoveChild(srcID, readmeID, 0), "Files can't have children")
+}
+
+func TestFSTreeModel_ReadOnly(t *testing.T) {
+	assert := testify.New(t)
+	root, err := NewFSTreeModel(testFS(), "project")
+	assert.NoError(err)
+	readme := root.Children()[6].(*FSTreeModel)
+	assert.ErrorIs(readme.SetDisplayString("README.txt"), ErrReadOnly)
+	assert.ErrorIs(readme.Remove(), ErrReadOnly)
+	_, err = root.Mkdir("docs")
+	assert.ErrorIs(err, ErrReadOnly)
+}
+
+func TestFSTreeModel_Writable(t *testing.T) {
+	assert := testify.New(t)
+	dir := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(dir, "src", "pkg"), 0755))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "src", "pkg", "a.go"), []byte("package pkg"), 0644))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644))
+
+	root, err := NewFSTreeModel(DirFS(dir), ".")
+	assert.NoError(err)
+	assert.True(root.Writable())
+	assert.Equal(filepath.Base(dir), root.DisplayString(), "The root of a DirFS should be named after its directory")
+	assert.ErrorIs(root.SetDisplayString("other"), ErrInvalidName)
+	assert.ErrorIs(root.Remove(), fs.ErrPermission)
+
+	reg := NewTreeModelRegistry()
+	rootID, err := reg.AddChild(ModelRoot, root)
+	assert.NoError(err)
+	reg.Load(rootID)
+	srcID := reg.Children(rootID)[0]
+	src := reg.Node(srcID).(*FSTreeModel)
+	reg.Load(srcID)
+	pkg := reg.Node(reg.Children(srcID)[0]).(*FSTreeModel)
+	pkg.Children()
+
+	var updated []widget.TreeNodeID
+	reg.AddListener(func(event RegistryEvent) {
+		if event.Kind == NodeUpdated {
+			updated = append(updated, event.ID)
+		}
+	})
+	assert.NoError(src.SetDisplayString("source"))
+	assert.Equal("source", src.DisplayString())
+	assert.Equal([]widget.TreeNodeID{srcID}, updated, "Renaming should update the node")
+	assert.Equal("source/pkg/a.go", pkg.Children()[0].(*FSTreeModel).Path(), "Descendants should follow a renamed directory")
+	_, err = os.Stat(filepath.Join(dir, "source", "pkg", "a.go"))
+	assert.NoError(err)
+	assert.ErrorIs(src.SetDisplayString("a/b"), ErrInvalidName)
+
+	notes := reg.Node(reg.Children(rootID)[1]).(*FSTreeModel)
+	assert.ErrorIs(src.SetDisplayString("notes.txt"), fs.ErrExist, "Renaming onto a sibling shouldn't replace it")
+	assert.ErrorIs(notes.SetDisplayString("source"), fs.ErrExist)
+	assert.Equal("source", src.DisplayString())
+	data, err := os.ReadFile(filepath.Join(dir, "notes.txt"))
+	assert.NoError(err)
+	assert.Equal("notes", string(data))
+	_, err = os.Stat(filepath.Join(dir, "source", "pkg", "a.go"))
+	assert.NoError(err)
+	assert.NoError(notes.SetDisplayString("notes.txt"), "Keeping the same name should be allowed")
+	assert.ErrorIs(src.SetDisplayString(".."), ErrInvalidName)
+
+	docs, err := root.Mkdir("docs")
+	assert.NoError(err)
+	assert.True(docs.IsDir())
+	docsID, err := reg.AddChild(rootID, docs)
+	assert.NoError(err)
+	assert.Len(reg.Children(rootID), 3, "The new directory should only be registered once")
+	assert.Equal(docs, reg.Node(docsID))
+	_, err = os.Stat(filepath.Join(dir, "docs"))
+	assert.NoError(err)
+	_, err = reg.Node(reg.Children(rootID)[1]).(*FSTreeModel).Mkdir("file")
+	assert.ErrorIs(err, ErrNotDir)
+
+	assert.NoError(src.Remove())
+	reg.RemoveChild(srcID)
+	_, err = os.Stat(filepath.Join(dir, "source"))
+	assert.ErrorIs(err, fs.ErrNotExist)
+	assert.Equal([]string{"notes.txt", "docs"}, displayStrings(root.Children()))
+}
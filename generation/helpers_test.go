@@ -0,0 +1,52 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+)
+
+// renamableModel is a ModelData that can be renamed to anything but an empty string.
+type renamableModel struct {
+	ModelData
+}
+
+func (m *renamableModel) SetDisplayString(name string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	m.Data = name
+	m.NotifyChanged()
+	return nil
+}
+
+// buildModels creates the models most tests use: root, with children a and b, and a with children a1 and a2. root and
+// a can be renamed.
+func buildModels(t *testing.T) *renamableModel {
+	root := &renamableModel{ModelData{Data: "root"}}
+	a := &renamableModel{ModelData{Data: "a"}}
+	for _, child := range []TreeModel{a, &ModelData{Data: "b"}} {
+		if err := root.AddChild(child); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, child := range []TreeModel{&ModelData{Data: "a1"}, &ModelData{Data: "a2"}} {
+		if err := a.AddChild(child); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// buildTopLevelRegistry registers the children of the root from buildModels at the top level, with PathStrategy, so it
+// holds a, with children a1 and a2, and b. a is returned too.
+func buildTopLevelRegistry(t *testing.T) (*TreeModelRegistry, TreeModel) {
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	children := buildModels(t).Children()
+	for _, child := range children {
+		if _, err := reg.AddChild(ModelRoot, child); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return reg, children[0]
+}
@@ -0,0 +1,54 @@
+package generation
+
+import (
+	"fmt"
+	"net/url"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+)
+
+// IDStrategy creates the ID of a model being registered as a child of parentID. TreeModelRegistry makes IDs unique by
+// appending "#2", "#3" and so on to IDs that are already registered, and replaces an empty ID with a UUID.
+type IDStrategy func(parentID widget.TreeNodeID, model TreeModel) widget.TreeNodeID
+
+// IdentifiedTreeModel may be implemented by a TreeModel to provide its own ID, for use with ModelIDStrategy.
+type IdentifiedTreeModel interface {
+	TreeNodeID() widget.TreeNodeID // TreeNodeID returns an ID that's stable between runs, like a database key or file path.
+}
+
+// UUIDStrategy gives every node a random ID. This is the default strategy, but the IDs change every time the tree is
+// built, so they can't be used to persist anything.
+func UUIDStrategy(widget.TreeNodeID, TreeModel) widget.TreeNodeID {
+	id, err := uuid.NewRandom()
+	for err != nil {
+		panic(fmt.Errorf("failed to generate UUID: %v", err))
+	}
+	return id.String()
+}
+
+// PathStrategy builds IDs from the display strings of a node and its ancestors, escaped and separated by '/'.
+// The IDs are stable as long as the same models are added in the same order. They aren't changed when a node is renamed
+// or moved, so they'll only match its display path until then.
+func PathStrategy(parentID widget.TreeNodeID, model TreeModel) widget.TreeNodeID {
+	name := url.PathEscape(model.DisplayString())
+	if parentID == ModelRoot {
+		return name
+	}
+	return parentID + "/" + name
+}
+
+// ModelIDStrategy uses the IDs of models implementing IdentifiedTreeModel, and PathStrategy for any others.
+func ModelIDStrategy(parentID widget.TreeNodeID, model TreeModel) widget.TreeNodeID {
+	if identified, ok := model.(IdentifiedTreeModel); ok {
+		return identified.TreeNodeID()
+	}
+	return PathStrategy(parentID, model)
+}
+
+// SetIDStrategy changes how IDs are created for nodes registered from now on. A nil strategy restores UUIDStrategy.
+func (r *TreeModelRegistry) SetIDStrategy(strategy IDStrategy) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.idStrategy = strategy
+}
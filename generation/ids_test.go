@@ -0,0 +1,68 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+type identifiedModel struct {
+	ModelData
+	id widget.TreeNodeID
+}
+
+func (m *identifiedModel) TreeNodeID() widget.TreeNodeID {
+	return m.id
+}
+
+func TestPathStrategy(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+
+	root := &ModelData{Data: "root"}
+	assert.NoError(root.AddChild(&ModelData{Data: "existing"}))
+	rootID, err := reg.AddChild(ModelRoot, root)
+	assert.NoError(err)
+	assert.Equal("root", rootID)
+	assert.Equal([]widget.TreeNodeID{"root/existing"}, reg.Children(rootID), "Existing children should use the strategy")
+
+	slashID, err := reg.AddChild(rootID, &ModelData{Data: "a/b"})
+	assert.NoError(err)
+	assert.Equal("root/a%2Fb", slashID, "Separators in names should be escaped")
+
+	firstID, err := reg.AddChild(rootID, &ModelData{Data: "dup"})
+	assert.NoError(err)
+	secondID, err := reg.AddChild(rootID, &ModelData{Data: "dup"})
+	assert.NoError(err)
+	assert.Equal("root/dup", firstID)
+	assert.Equal("root/dup#2", secondID, "Duplicate IDs should be made unique")
+
+	unnamedID, err := reg.AddChild(ModelRoot, &ModelData{})
+	assert.NoError(err)
+	assert.NotEqual(ModelRoot, unnamedID, "An empty ID should be replaced")
+
+	reg.SetIDStrategy(nil)
+	uuidID, err := reg.AddChild(rootID, &ModelData{Data: "dup"})
+	assert.NoError(err)
+	assert.Len(uuidID, 36, "A nil strategy should restore UUIDs")
+}
+
+func TestModelIDStrategy(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(ModelIDStrategy)
+
+	id, err := reg.AddChild(ModelRoot, &identifiedModel{ModelData: ModelData{Data: "root"}, id: "key-1"})
+	assert.NoError(err)
+	assert.Equal("key-1", id)
+
+	childID, err := reg.AddChild(id, &ModelData{Data: "child"})
+	assert.NoError(err)
+	assert.Equal("key-1/child", childID, "Models without their own ID should fall back to paths")
+
+	dupID, err := reg.AddChild(ModelRoot, &identifiedModel{id: "key-1"})
+	assert.NoError(err)
+	assert.Equal("key-1#2", dupID)
+}
@@ -0,0 +1,556 @@
+package generation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"github.com/pkg/errors"
+)
+
+var _ RenamableTreeModel = (*JSONTreeModel)(nil)
+var _ SubtitledTreeModel = (*JSONTreeModel)(nil)
+var _ ChildPolicy = (*JSONTreeModel)(nil)
+
+var (
+	ErrUnsupportedValue = errors.New("unsupported value")
+	ErrDuplicateKey     = errors.New("duplicate key")
+)
+
+// JSONKind is the kind of value held by a JSONTreeModel.
+type JSONKind int
+
+const (
+	JSONObject JSONKind = iota
+	JSONArray
+	JSONString
+	JSONNumber
+	JSONBool
+	JSONNull
+)
+
+func (k JSONKind) String() string {
+	switch k {
+	case JSONObject:
+		return "object"
+	case JSONArray:
+		return "array"
+	case JSONString:
+		return "string"
+	case JSONNumber:
+		return "number"
+	case JSONBool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// JSONTreeModel is a node of a JSON document, where objects and arrays have a child for each member or element. Object
+// members keep their order, and are labelled with their key, array elements with their index, and scalars with their
+// value. Scalars can be edited by renaming them, and nodes can be moved, added and removed through the registry, with
+// the changes written back out by MarshalJSON and Write.
+type JSONTreeModel struct {
+	BaseTreeModel
+
+	mux    sync.RWMutex
+	key    string
+	kind   JSONKind
+	value  interface{} // value is a string, json.Number, bool or nil, depending on kind.
+	parent *JSONTreeModel
+}
+
+// ReadJSON reads a single JSON value from r, keeping the order of object members.
+func ReadJSON(r io.Reader) (*JSONTreeModel, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	m, err := readJSONValue(dec, "")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return m, nil
+}
+
+func readJSONValue(dec *json.Decoder, key string) (*JSONTreeModel, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		m := &JSONTreeModel{key: key, kind: JSONObject}
+		if t == '[' {
+			m.kind = JSONArray
+		}
+		for dec.More() {
+			childKey := ""
+			if m.kind == JSONObject {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				childKey = keyToken.(string)
+			}
+			child, err := readJSONValue(dec, childKey)
+			if err != nil {
+				return nil, err
+			}
+			m.appendChild(child)
+		}
+		// The closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return m, nil
+	default:
+		return NewJSONTreeModel(key, t)
+	}
+}
+
+// NewJSONTreeModel creates a model for a value decoded by encoding/json, or built the same way, with key used if it's
+// added to an object. Object members are sorted by key, since maps don't keep their order.
+func NewJSONTreeModel(key string, value interface{}) (*JSONTreeModel, error) {
+	m := &JSONTreeModel{key: key}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m.kind = JSONObject
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child, err := NewJSONTreeModel(k, v[k])
+			if err != nil {
+				return nil, err
+			}
+			m.appendChild(child)
+		}
+	case []interface{}:
+		m.kind = JSONArray
+		for _, element := range v {
+			child, err := NewJSONTreeModel("", element)
+			if err != nil {
+				return nil, err
+			}
+			m.appendChild(child)
+		}
+	case string:
+		m.kind, m.value = JSONString, v
+	case json.Number:
+		m.kind, m.value = JSONNumber, v
+	case float64:
+		m.kind, m.value = JSONNumber, json.Number(strconv.FormatFloat(v, 'g', -1, 64))
+	case int:
+		m.kind, m.value = JSONNumber, json.Number(strconv.Itoa(v))
+	case bool:
+		m.kind, m.value = JSONBool, v
+	case nil:
+		m.kind = JSONNull
+	default:
+		return nil, errors.Wrapf(ErrUnsupportedValue, "%T", value)
+	}
+	return m, nil
+}
+
+// Kind returns the kind of value held by the model.
+func (m *JSONTreeModel) Kind() JSONKind {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.kind
+}
+
+// Key returns the model's key in its object, which is empty for the root and array elements created without one.
+func (m *JSONTreeModel) Key() string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.key
+}
+
+// Value returns the model as the value encoding/json would decode it to with UseNumber, so objects are maps and arrays
+// are slices.
+func (m *JSONTreeModel) Value() interface{} {
+	switch kind, value := m.scalar(); kind {
+	case JSONObject:
+		object := map[string]interface{}{}
+		for _, child := range m.jsonChildren() {
+			object[child.Key()] = child.Value()
+		}
+		return object
+	case JSONArray:
+		array := []interface{}{}
+		for _, child := range m.jsonChildren() {
+			array = append(array, child.Value())
+		}
+		return array
+	default:
+		return value
+	}
+}
+
+// MarshalJSON encodes the model, keeping the order of object members.
+func (m *JSONTreeModel) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	kind, value := m.scalar()
+	switch kind {
+	case JSONObject, JSONArray:
+		openDelim, closeDelim := byte('{'), byte('}')
+		if kind == JSONArray {
+			openDelim, closeDelim = '[', ']'
+		}
+		buf.WriteByte(openDelim)
+		for i, child := range m.jsonChildren() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if kind == JSONObject {
+				key, _ := json.Marshal(child.Key())
+				buf.Write(key)
+				buf.WriteByte(':')
+			}
+			data, err := child.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(data)
+		}
+		buf.WriteByte(closeDelim)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes the model to w as indented JSON.
+func (m *JSONTreeModel) Write(w io.Writer) error {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func (m *JSONTreeModel) DisplayIcon() fyne.Resource {
+	switch kind, value := m.scalar(); kind {
+	case JSONObject:
+		return theme.GridIcon()
+	case JSONArray:
+		return theme.ListIcon()
+	case JSONString:
+		return theme.FileTextIcon()
+	case JSONNumber:
+		return theme.ContentAddIcon()
+	case JSONBool:
+		if value == true {
+			return theme.CheckButtonCheckedIcon()
+		}
+		return theme.CheckButtonIcon()
+	default:
+		return theme.ContentClearIcon()
+	}
+}
+
+// DisplayString shows the key of object members as a JSON string, or the index of array elements, followed by the
+// value of scalars. The root is shown as "$".
+func (m *JSONTreeModel) DisplayString() string {
+	label := m.label()
+	kind, value := m.scalar()
+	if kind == JSONObject || kind == JSONArray {
+		if label == "" {
+			return "$"
+		}
+		return label
+	}
+	literal, _ := json.Marshal(value)
+	if label == "" {
+		return string(literal)
+	}
+	return fmt.Sprintf("%s: %s", label, literal)
+}
+
+// DisplaySubtitle shows the kind of value, and the number of children of objects and arrays.
+func (m *JSONTreeModel) DisplaySubtitle() string {
+	kind := m.Kind()
+	if kind == JSONObject || kind == JSONArray {
+		return fmt.Sprintf("%s (%d)", kind, len(m.Children()))
+	}
+	return kind.String()
+}
+
+// SetDisplayString changes the key of an object member and the value of a scalar, given text like the display string.
+// In objects, text starting with a quote starts with the key as a JSON string, which may be followed by ':' and the
+// value. Other text is the value, keeping the key. Values are parsed as JSON literals, or used as a string if they
+// aren't valid JSON. An array element's own index label is left out of its value.
+func (m *JSONTreeModel) SetDisplayString(text string) error {
+	inObject := m.parentKind() == JSONObject
+	kind := m.Kind()
+	key, valueText := m.Key(), text
+	switch {
+	case kind == JSONObject || kind == JSONArray:
+		if !inObject {
+			return errors.New("only object members can be renamed")
+		}
+		var rest string
+		var err error
+		if key, rest, err = cutJSONKey(text); err != nil {
+			return err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return errors.New("objects and arrays only have a key")
+		}
+	case inObject:
+		if strings.HasPrefix(strings.TrimSpace(text), `"`) {
+			var rest string
+			var err error
+			if key, rest, err = cutJSONKey(text); err != nil {
+				return err
+			}
+			rest = strings.TrimSpace(rest)
+			switch {
+			case rest == "":
+				valueText = m.valueLiteral()
+			case strings.HasPrefix(rest, ":"):
+				valueText = rest[1:]
+			default:
+				return errors.New("expected ':' after the key")
+			}
+		}
+	default:
+		if label := m.label() + ":"; label != ":" && strings.HasPrefix(strings.TrimSpace(text), label) {
+			valueText = strings.TrimPrefix(strings.TrimSpace(text), label)
+		}
+	}
+	if inObject {
+		if key == "" {
+			return errors.New("keys can't be empty")
+		}
+		if m.parent.hasKey(key, m) {
+			return errors.Wrapf(ErrDuplicateKey, "'%s'", key)
+		}
+	}
+	newKind, newValue := kind, interface{}(nil)
+	if kind != JSONObject && kind != JSONArray {
+		var err error
+		newKind, newValue, err = parseJSONScalar(valueText)
+		if err != nil {
+			return err
+		}
+	}
+	m.mux.Lock()
+	m.key = key
+	if kind != JSONObject && kind != JSONArray {
+		m.kind, m.value = newKind, newValue
+	}
+	m.mux.Unlock()
+	m.NotifyChanged()
+	return nil
+}
+
+// cutJSONKey parses the JSON string at the start of text, returning it along with the rest of text.
+func cutJSONKey(text string) (string, string, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	var key string
+	if err := dec.Decode(&key); err != nil {
+		return "", "", errors.Wrap(err, "keys must be JSON strings")
+	}
+	return key, text[dec.InputOffset():], nil
+}
+
+// parseJSONScalar parses text as a JSON literal, falling back to a string.
+func parseJSONScalar(text string) (JSONKind, interface{}, error) {
+	trimmed := strings.TrimSpace(text)
+	if !json.Valid([]byte(trimmed)) {
+		return JSONString, trimmed, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return 0, nil, err
+	}
+	m, err := NewJSONTreeModel("", value)
+	if err != nil {
+		return 0, nil, err
+	}
+	if m.kind == JSONObject || m.kind == JSONArray {
+		return 0, nil, errors.Wrap(ErrUnsupportedValue, "only scalar values can be entered")
+	}
+	return m.kind, m.value, nil
+}
+
+func (m *JSONTreeModel) AddChild(child TreeModel) error {
+	if err := m.BaseTreeModel.AddChild(child); err != nil {
+		return err
+	}
+	m.adopt(child)
+	m.renumber()
+	return nil
+}
+
+func (m *JSONTreeModel) AddChildAt(index int, child TreeModel) error {
+	if err := m.BaseTreeModel.AddChildAt(index, child); err != nil {
+		return err
+	}
+	m.adopt(child)
+	m.renumber()
+	return nil
+}
+
+func (m *JSONTreeModel) RemoveChild() TreeModel {
+	removed := m.BaseTreeModel.RemoveChild()
+	m.release(removed)
+	m.renumber()
+	return removed
+}
+
+func (m *JSONTreeModel) RemoveChildAt(index int) TreeModel {
+	removed := m.BaseTreeModel.RemoveChildAt(index)
+	m.release(removed)
+	m.renumber()
+	return removed
+}
+
+// appendChild adds a child while a model is being built, before anything can be listening to it.
+func (m *JSONTreeModel) appendChild(child TreeModel) {
+	_ = m.BaseTreeModel.AddChild(child)
+	m.adopt(child)
+}
+
+// renumber notifies listeners after children are added or removed, since the model's subtitle shows how many there
+// are, and the label of every array element shows its index.
+func (m *JSONTreeModel) renumber() {
+	m.NotifyChanged()
+	if m.Kind() != JSONArray {
+		return
+	}
+	for _, child := range m.jsonChildren() {
+		child.NotifyChanged()
+	}
+}
+
+// CanAccept accepts JSON values in objects and arrays, and requires object members to have a unique key.
+func (m *JSONTreeModel) CanAccept(child TreeModel) bool {
+	jsonChild, ok := child.(*JSONTreeModel)
+	if !ok {
+		return false
+	}
+	switch m.Kind() {
+	case JSONObject:
+		key := jsonChild.Key()
+		return key != "" && !m.hasKey(key, jsonChild)
+	case JSONArray:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *JSONTreeModel) MaxChildren() int {
+	if kind := m.Kind(); kind == JSONObject || kind == JSONArray {
+		return Unlimited
+	}
+	return 0
+}
+
+func (m *JSONTreeModel) AllowedTypes() []TreeModel {
+	return []TreeModel{(*JSONTreeModel)(nil)}
+}
+
+func (m *JSONTreeModel) adopt(child TreeModel) {
+	if jsonChild, ok := child.(*JSONTreeModel); ok {
+		jsonChild.mux.Lock()
+		jsonChild.parent = m
+		jsonChild.mux.Unlock()
+	}
+}
+
+func (m *JSONTreeModel) release(child TreeModel) {
+	if jsonChild, ok := child.(*JSONTreeModel); ok && jsonChild != nil {
+		jsonChild.mux.Lock()
+		jsonChild.parent = nil
+		jsonChild.mux.Unlock()
+	}
+}
+
+func (m *JSONTreeModel) scalar() (JSONKind, interface{}) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.kind, m.value
+}
+
+func (m *JSONTreeModel) valueLiteral() string {
+	_, value := m.scalar()
+	literal, _ := json.Marshal(value)
+	return string(literal)
+}
+
+func (m *JSONTreeModel) getParent() *JSONTreeModel {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.parent
+}
+
+func (m *JSONTreeModel) parentKind() JSONKind {
+	if parent := m.getParent(); parent != nil {
+		return parent.Kind()
+	}
+	return JSONNull
+}
+
+// label is the key of an object member, or the index of an array element.
+func (m *JSONTreeModel) label() string {
+	parent := m.getParent()
+	if parent != nil && parent.Kind() == JSONArray {
+		for i, sibling := range parent.Children() {
+			if sibling == m {
+				return fmt.Sprintf("[%d]", i)
+			}
+		}
+	}
+	key := m.Key()
+	if key == "" {
+		return ""
+	}
+	literal, _ := json.Marshal(key)
+	return string(literal)
+}
+
+func (m *JSONTreeModel) jsonChildren() []*JSONTreeModel {
+	var children []*JSONTreeModel
+	for _, child := range m.Children() {
+		if jsonChild, ok := child.(*JSONTreeModel); ok {
+			children = append(children, jsonChild)
+		}
+	}
+	return children
+}
+
+// hasKey reports whether a child other than except has key.
+func (m *JSONTreeModel) hasKey(key string, except *JSONTreeModel) bool {
+	for _, child := range m.jsonChildren() {
+		if child != except && child.Key() == key {
+			return true
+		}
+	}
+	return false
+}
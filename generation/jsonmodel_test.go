@@ -0,0 +1,186 @@
+package generation
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/theme"
+	testify "github.com/stretchr/testify/require"
+)
+
+const testJSON = `{"name": "tree", "version": 1.5, "tags": ["a", true, null], "meta": {}}`
+
+func TestReadJSON(t *testing.T) {
+	assert := testify.New(t)
+	root, err := ReadJSON(strings.NewReader(testJSON))
+	assert.NoError(err)
+	assert.Equal(JSONObject, root.Kind())
+	assert.Equal("$", root.DisplayString())
+	assert.Equal("object (4)", root.DisplaySubtitle())
+	assert.Equal(theme.GridIcon(), root.DisplayIcon())
+	assert.Equal([]string{`"name": "tree"`, `"version": 1.5`, `"tags"`, `"meta"`}, displayStrings(root.Children()), "Members should keep their order")
+
+	tags := root.Children()[2].(*JSONTreeModel)
+	assert.Equal(theme.ListIcon(), tags.DisplayIcon())
+	assert.Equal("array (3)", tags.DisplaySubtitle())
+	assert.Equal([]string{`[0]: "a"`, "[1]: true", "[2]: null"}, displayStrings(tags.Children()))
+	assert.Equal(theme.CheckButtonCheckedIcon(), tags.Children()[1].DisplayIcon())
+	assert.Equal("boolean", tags.Children()[1].(*JSONTreeModel).DisplaySubtitle())
+	assert.Equal(json.Number("1.5"), root.Children()[1].(*JSONTreeModel).Value())
+
+	_, err = ReadJSON(strings.NewReader(`{"a": }`))
+	assert.Error(err)
+	_, err = ReadJSON(strings.NewReader(`{} []`))
+	assert.Error(err, "Only a single value should be read")
+}
+
+func TestNewJSONTreeModel(t *testing.T) {
+	assert := testify.New(t)
+	var value interface{}
+	assert.NoError(json.Unmarshal([]byte(testJSON), &value))
+	root, err := NewJSONTreeModel("", value)
+	assert.NoError(err)
+	assert.Equal([]string{`"meta"`, `"name": "tree"`, `"tags"`, `"version": 1.5`}, displayStrings(root.Children()), "Map keys should be sorted")
+	assert.Equal(value, normalizeJSON(t, root.Value()))
+
+	scalar, err := NewJSONTreeModel("", 3)
+	assert.NoError(err)
+	assert.Equal("3", scalar.DisplayString())
+	_, err = NewJSONTreeModel("", struct{}{})
+	assert.ErrorIs(err, ErrUnsupportedValue)
+}
+
+// normalizeJSON round trips value through encoding/json, so numbers are float64.
+func normalizeJSON(t *testing.T, value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	testify.NoError(t, err)
+	var normalized interface{}
+	testify.NoError(t, json.Unmarshal(data, &normalized))
+	return normalized
+}
+
+func TestJSONTreeModel_SetDisplayString(t *testing.T) {
+	assert := testify.New(t)
+	root, err := ReadJSON(strings.NewReader(testJSON))
+	assert.NoError(err)
+	name := root.Children()[0].(*JSONTreeModel)
+	version := root.Children()[1].(*JSONTreeModel)
+	tags := root.Children()[2].(*JSONTreeModel)
+
+	assert.NoError(name.SetDisplayString(`"title": 42`))
+	assert.Equal("title", name.Key())
+	assert.Equal(JSONNumber, name.Kind())
+	assert.NoError(name.SetDisplayString(`"label"`))
+	assert.Equal(`"label": 42`, name.DisplayString(), "The value should be kept if only the key is given")
+	assert.NoError(version.SetDisplayString(`"version": two`))
+	assert.Equal(`"version": "two"`, version.DisplayString(), "Invalid JSON should be used as a string")
+	assert.ErrorIs(version.SetDisplayString(`"label": 1`), ErrDuplicateKey)
+	assert.Error(version.SetDisplayString(`"": 1`))
+	assert.Error(version.SetDisplayString(`"version" 1`))
+	assert.ErrorIs(version.SetDisplayString(`"version": [1]`), ErrUnsupportedValue)
+	assert.NoError(version.SetDisplayString("7"))
+	assert.Equal(`"version": 7`, version.DisplayString(), "Text without a key should be the value")
+	assert.NoError(version.SetDisplayString(`"version": "two"`))
+
+	assert.NoError(name.SetDisplayString(`"a:b \"c\"": 42`))
+	assert.Equal(`a:b "c"`, name.Key(), "Keys may contain colons and quotes")
+	assert.Equal(`"a:b \"c\"": 42`, name.DisplayString())
+	assert.NoError(name.SetDisplayString(name.DisplayString()))
+	assert.Equal(`a:b "c"`, name.Key(), "The display string should set the same key")
+	assert.NoError(name.SetDisplayString(`"label": 42`))
+
+	first := tags.Children()[0].(*JSONTreeModel)
+	assert.NoError(first.SetDisplayString(`[0]: false`))
+	assert.Equal("[0]: false", first.DisplayString())
+	assert.NoError(first.SetDisplayString(`"[x]"`))
+	assert.Equal(`[0]: "[x]"`, first.DisplayString())
+	assert.NoError(first.SetDisplayString(`"[1]: y"`))
+	assert.Equal(`[0]: "[1]: y"`, first.DisplayString(), "Only the element's own index should be left out")
+	assert.NoError(first.SetDisplayString(`"[x]"`))
+	assert.Error(tags.Children()[1].(*JSONTreeModel).SetDisplayString("[1]"), "Array elements can't be renamed")
+	assert.Error(tags.SetDisplayString("labels"), "Keys should be JSON strings")
+	assert.Error(tags.SetDisplayString(`"labels": 1`), "Objects and arrays don't have a value")
+	assert.NoError(tags.SetDisplayString(`"labels"`))
+	assert.Equal("labels", tags.Key())
+	assert.Error(root.SetDisplayString("root"))
+
+	var buf bytes.Buffer
+	assert.NoError(root.Write(&buf))
+	assert.Equal(`{
+  "label": 42,
+  "version": "two",
+  "labels": [
+    "[x]",
+    true,
+    null
+  ],
+  "meta": {}
+}
+`, buf.String())
+}
+
+func TestJSONTreeModel_Registry(t *testing.T) {
+	assert := testify.New(t)
+	root, err := ReadJSON(strings.NewReader(testJSON))
+	assert.NoError(err)
+	reg := NewTreeModelRegistry()
+	rootID, err := reg.AddChild(ModelRoot, root)
+	assert.NoError(err)
+	ids := reg.Children(rootID)
+	nameID, versionID, tagsID, metaID := ids[0], ids[1], ids[2], ids[3]
+
+	_, err = reg.AddChild(nameID, &JSONTreeModel{key: "x", kind: JSONNull})
+	assert.Error(err, "Scalars can't have children")
+	_, err = reg.AddChild(metaID, &JSONTreeModel{kind: JSONNull})
+	assert.Error(err, "Object members need a key")
+	_, err = reg.AddChild(metaID, &ModelData{Data: "other"})
+	assert.Error(err)
+	assert.Error(reg.MoveChild(tagsID, versionID, 0))
+	assert.NoError(reg.MoveChild(versionID, metaID, 0))
+	assert.Error(reg.MoveChild(reg.Children(tagsID)[0], metaID, 0), "Array elements have no key")
+	assert.NoError(reg.MoveChild(nameID, tagsID, 0))
+	assert.Equal(`[0]: "tree"`, reg.Node(nameID).DisplayString(), "Moved nodes should be labelled by their new parent")
+
+	added, err := NewJSONTreeModel("version", 2)
+	assert.NoError(err)
+	_, err = reg.AddChild(metaID, added)
+	assert.Error(err, "Keys should be unique")
+	_, err = reg.AddChild(rootID, added)
+	assert.NoError(err)
+
+	data, err := json.Marshal(root)
+	assert.NoError(err)
+	assert.Equal(`{"tags":["tree","a",true,null],"meta":{"version":1.5},"version":2}`, string(data))
+}
+
+func TestJSONTreeModel_Renumber(t *testing.T) {
+	assert := testify.New(t)
+	root, err := ReadJSON(strings.NewReader(testJSON))
+	assert.NoError(err)
+	reg := NewTreeModelRegistry()
+	rootID, err := reg.AddChild(ModelRoot, root)
+	assert.NoError(err)
+	tagsID := reg.Children(rootID)[2]
+	elements := reg.Children(tagsID)
+
+	updated := map[string]bool{}
+	reg.AddListener(func(event RegistryEvent) {
+		if event.Kind == NodeUpdated {
+			updated[event.ID] = true
+		}
+	})
+	assert.NoError(reg.MoveChild(elements[0], tagsID, 2))
+	assert.Equal([]string{"[0]: true", "[1]: null", `[2]: "a"`}, displayStrings(reg.Node(tagsID).Children()))
+	for _, id := range elements {
+		assert.True(updated[id], "Every element's index should be updated")
+	}
+
+	updated = map[string]bool{}
+	reg.RemoveChild(elements[1])
+	assert.Equal([]string{"[0]: null", `[1]: "a"`}, displayStrings(reg.Node(tagsID).Children()))
+	assert.True(updated[elements[2]])
+	assert.True(updated[elements[0]])
+	assert.True(updated[tagsID], "The array's subtitle shows its length")
+}
@@ -0,0 +1,85 @@
+package generation
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// TypeAheadTimeout is how long a TypeAheadBuffer waits for the next rune before starting a new prefix.
+const TypeAheadTimeout = time.Second
+
+// TypeAheadBuffer collects typed runes into a prefix for jumping to a node by its display string.
+type TypeAheadBuffer struct {
+	mux    sync.Mutex
+	prefix []rune
+	last   time.Time
+}
+
+// Add appends r to the prefix, unless it's been longer than TypeAheadTimeout since the last rune, and returns the
+// prefix. Typing the same rune repeatedly returns just that rune, so it can be used to cycle through matching nodes.
+func (b *TypeAheadBuffer) Add(r rune, now time.Time) string {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if now.Sub(b.last) > TypeAheadTimeout {
+		b.prefix = b.prefix[:0]
+	}
+	b.last = now
+	b.prefix = append(b.prefix, r)
+	for _, pr := range b.prefix {
+		if pr != r {
+			return string(b.prefix)
+		}
+	}
+	return string(r)
+}
+
+// Neighbor returns the ID delta places away from id in order, stopping at either end. If id isn't in order, the first
+// ID is returned for a positive delta, and the last otherwise. An empty order returns an empty ID.
+func Neighbor(order []widget.TreeNodeID, id widget.TreeNodeID, delta int) widget.TreeNodeID {
+	if len(order) == 0 {
+		return ModelRoot
+	}
+	index := indexOfID(order, id)
+	switch {
+	case index < 0 && delta > 0:
+		index = 0
+	case index < 0:
+		index = len(order) - 1
+	default:
+		index += delta
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(order) {
+		index = len(order) - 1
+	}
+	return order[index]
+}
+
+// MatchPrefix finds the first ID in order whose display string starts with prefix, ignoring case. The search starts at
+// from and wraps around, so typing more of a prefix keeps the current match. A single rune prefix starts after from
+// instead, so repeating it cycles through the matches. An empty ID is returned if nothing matches.
+func (r *TreeModelRegistry) MatchPrefix(order []widget.TreeNodeID, from widget.TreeNodeID, prefix string) widget.TreeNodeID {
+	if prefix == "" || len(order) == 0 {
+		return ModelRoot
+	}
+	prefix = strings.ToLower(prefix)
+	start := indexOfID(order, from)
+	if start < 0 {
+		start = 0
+	} else if utf8.RuneCountInString(prefix) == 1 {
+		start++
+	}
+	for i := 0; i < len(order); i++ {
+		id := order[(start+i)%len(order)]
+		if model := r.Node(id); model != nil && strings.HasPrefix(strings.ToLower(model.DisplayString()), prefix) {
+			return id
+		}
+	}
+	return ModelRoot
+}
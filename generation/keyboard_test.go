@@ -0,0 +1,69 @@
+package generation
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestTypeAheadBuffer_Add(t *testing.T) {
+	assert := testify.New(t)
+	var buf TypeAheadBuffer
+	now := time.Now()
+
+	assert.Equal("a", buf.Add('a', now))
+	assert.Equal("ab", buf.Add('b', now.Add(TypeAheadTimeout/2)))
+	assert.Equal("c", buf.Add('c', now.Add(TypeAheadTimeout*2)), "The prefix should reset after the timeout")
+
+	now = now.Add(TypeAheadTimeout * 4)
+	assert.Equal("d", buf.Add('d', now))
+	assert.Equal("d", buf.Add('d', now), "Repeating a rune should return only that rune")
+	assert.Equal("ddx", buf.Add('x', now))
+}
+
+func TestNeighbor(t *testing.T) {
+	order := []widget.TreeNodeID{"a", "b", "c"}
+	tests := map[string]struct {
+		order    []widget.TreeNodeID
+		id       widget.TreeNodeID
+		delta    int
+		expected widget.TreeNodeID
+	}{
+		"Next":           {order: order, id: "a", delta: 1, expected: "b"},
+		"Previous":       {order: order, id: "b", delta: -1, expected: "a"},
+		"Past the end":   {order: order, id: "c", delta: 1, expected: "c"},
+		"Past the start": {order: order, id: "a", delta: -5, expected: "a"},
+		"Unknown next":   {order: order, id: "x", delta: 1, expected: "a"},
+		"Unknown prev":   {order: order, id: "x", delta: -1, expected: "c"},
+		"Empty order":    {id: "a", delta: 1, expected: ModelRoot},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			testify.Equal(t, tc.expected, Neighbor(tc.order, tc.id, tc.delta))
+		})
+	}
+}
+
+func TestTreeModelRegistry_MatchPrefix(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	var order []widget.TreeNodeID
+	for _, name := range []string{"Apple", "banana", "Apricot", "blueberry"} {
+		id, err := reg.AddChild(ModelRoot, &ModelData{Data: name})
+		assert.NoError(err)
+		order = append(order, id)
+	}
+	apple, banana, apricot, blueberry := order[0], order[1], order[2], order[3]
+
+	assert.Equal(apple, reg.MatchPrefix(order, ModelRoot, "a"), "Nothing current should search from the start")
+	assert.Equal(apricot, reg.MatchPrefix(order, apple, "a"), "A single rune should move to the next match")
+	assert.Equal(apple, reg.MatchPrefix(order, apricot, "A"), "The search should wrap and ignore case")
+	assert.Equal(apple, reg.MatchPrefix(order, apple, "ap"), "A longer prefix should keep the current match")
+	assert.Equal(apricot, reg.MatchPrefix(order, apple, "apr"))
+	assert.Equal(blueberry, reg.MatchPrefix(order, banana, "b"))
+	assert.Equal(ModelRoot, reg.MatchPrefix(order, apple, "cherry"))
+	assert.Equal(ModelRoot, reg.MatchPrefix(order, apple, ""))
+}
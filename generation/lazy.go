@@ -0,0 +1,44 @@
+package generation
+
+import "fyne.io/fyne/v2/widget"
+
+// LazyTreeModel may be implemented by models whose children are expensive to find, like directories. The registry
+// doesn't ask for their children until the node is loaded, which trees do when its branch is first opened, or before
+// a child is added or moved to it.
+type LazyTreeModel interface {
+	TreeModel
+	MayHaveChildren() bool // MayHaveChildren reports whether the model could have children without finding them, so an unloaded node can still be shown as a branch.
+}
+
+// Load registers the children of a LazyTreeModel that hasn't been loaded yet, and sends NodeLoaded. Nothing happens
+// for other nodes, so it's safe to call for any ID. The children are found before the registry is locked, since that
+// may be slow, so a node loaded or removed in the meantime is left as it is.
+func (r *TreeModelRegistry) Load(nodeID widget.TreeNodeID) {
+	r.mux.RLock()
+	model, unloaded := r.idMap[nodeID], r.unloaded[nodeID]
+	r.mux.RUnlock()
+	if !unloaded {
+		return
+	}
+	children := model.Children()
+	r.lock()
+	if !r.unloaded[nodeID] || r.idMap[nodeID] != model {
+		r.unlock()
+		return
+	}
+	delete(r.unloaded, nodeID)
+	for _, child := range children {
+		r.buildParentLinkage(nodeID, child, r.getID(nodeID, child))
+	}
+	held := r.unlock()
+	r.notify(RegistryEvent{Kind: NodeLoaded, ID: nodeID, ParentID: r.Parent(nodeID)})
+	r.sendHeld(held)
+}
+
+// IsLoaded reports whether the children of nodeID are registered, which is only false for a LazyTreeModel that
+// hasn't been loaded.
+func (r *TreeModelRegistry) IsLoaded(nodeID widget.TreeNodeID) bool {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return !r.unloaded[nodeID]
+}
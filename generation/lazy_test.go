@@ -0,0 +1,134 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+// lazyModel counts how often its children are asked for, and calls onRead while they are.
+type lazyModel struct {
+	ModelData
+	reads  int
+	onRead func()
+}
+
+func (l *lazyModel) Children() []TreeModel {
+	l.reads++
+	if l.onRead != nil {
+		l.onRead()
+	}
+	return l.ModelData.Children()
+}
+
+func (l *lazyModel) MayHaveChildren() bool {
+	return true
+}
+
+func TestTreeModelRegistry_Load(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	lazy := &lazyModel{ModelData: ModelData{Data: "lazy"}}
+	assert.NoError(lazy.AddChild(&ModelData{Data: "a"}))
+	var events []RegistryEvent
+	reg.AddListener(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	id, err := reg.AddChild(ModelRoot, lazy)
+	assert.NoError(err)
+	assert.Equal(0, lazy.reads, "Children shouldn't be read before loading")
+	assert.False(reg.IsLoaded(id))
+	assert.True(reg.HasChildren(id), "Unloaded nodes should be branches if they may have children")
+	assert.Empty(reg.Children(id))
+
+	reg.Load(id)
+	assert.True(reg.IsLoaded(id))
+	assert.Equal([]widget.TreeNodeID{"lazy/a"}, reg.Children(id))
+	assert.Equal(RegistryEvent{Kind: NodeLoaded, ID: id}, events[len(events)-1])
+	reg.Load(id)
+	assert.Equal(1, lazy.reads, "Loading again should do nothing")
+	assert.True(reg.IsLoaded("lazy/a"), "Other models are always loaded")
+}
+
+func TestTreeModelRegistry_LoadUnlocked(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	lazy := &lazyModel{ModelData: ModelData{Data: "lazy"}}
+	assert.NoError(lazy.AddChild(&ModelData{Data: "a"}))
+	id, err := reg.AddChild(ModelRoot, lazy)
+	assert.NoError(err)
+
+	lazy.onRead = func() {
+		assert.False(reg.IsLoaded(id), "The registry should be readable while children are found")
+	}
+	reg.Load(id)
+	assert.True(reg.IsLoaded(id))
+	assert.Equal([]widget.TreeNodeID{"lazy/a"}, reg.Children(id))
+
+	removed := &lazyModel{ModelData: ModelData{Data: "removed"}}
+	assert.NoError(removed.AddChild(&ModelData{Data: "b"}))
+	removedID, err := reg.AddChild(ModelRoot, removed)
+	assert.NoError(err)
+	removed.onRead = func() {
+		reg.RemoveChild(removedID)
+	}
+	reg.Load(removedID)
+	assert.Nil(reg.Node("removed/b"), "Children of a node removed while loading shouldn't be registered")
+}
+
+func TestTreeModelRegistry_LoadBeforeAdding(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	lazy := &lazyModel{ModelData: ModelData{Data: "lazy"}}
+	assert.NoError(lazy.AddChild(&ModelData{Data: "a"}))
+	id, err := reg.AddChild(ModelRoot, lazy)
+	assert.NoError(err)
+	other, err := reg.AddChild(ModelRoot, &ModelData{Data: "b"})
+	assert.NoError(err)
+
+	_, err = reg.AddChild(id, &ModelData{Data: "c"})
+	assert.NoError(err)
+	assert.Equal([]widget.TreeNodeID{"lazy/a", "lazy/c"}, reg.Children(id), "Existing children should be loaded before adding one")
+
+	lazy2 := &lazyModel{ModelData: ModelData{Data: "lazy2"}}
+	id2, err := reg.AddChild(ModelRoot, lazy2)
+	assert.NoError(err)
+	assert.NoError(reg.MoveChild(other, id2, 0))
+	assert.True(reg.IsLoaded(id2))
+	assert.Len(lazy2.Children(), 1)
+
+	reg.RemoveChild(id2)
+	assert.True(reg.IsLoaded(id2), "Removed nodes shouldn't be remembered")
+}
+
+func TestCheckSet_Load(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	lazy := &lazyModel{ModelData: ModelData{Data: "lazy"}}
+	assert.NoError(lazy.AddChild(&ModelData{Data: "a"}))
+	id, err := reg.AddChild(ModelRoot, lazy)
+	assert.NoError(err)
+	checks := NewCheckSet(reg)
+	checks.SetChecked(id, true)
+
+	reg.Load(id)
+	assert.Equal(Checked, checks.State("lazy/a"), "Loaded children of a checked node should be checked")
+}
+
+func TestTree_Load(t *testing.T) {
+	assert := testify.New(t)
+	lazy := &lazyModel{ModelData: ModelData{Data: "lazy"}}
+	assert.NoError(lazy.AddChild(&ModelData{Data: "a"}))
+	tree := NewTree(WithIDStrategy(PathStrategy), WithRoots(lazy))
+	assert.True(tree.IsBranch("lazy"))
+	assert.Equal(0, lazy.reads)
+
+	tree.OpenBranch("lazy")
+	assert.Equal([]widget.TreeNodeID{"lazy", "lazy/a"}, tree.visibleIDs(), "Opening a branch should load it")
+}
@@ -0,0 +1,160 @@
+package generation
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/drognisep/fynehelpers/layouthelp"
+)
+
+// DetailViewer may be implemented by a TreeModel to provide the view shown for it by a MasterDetail.
+type DetailViewer interface {
+	DetailView() fyne.CanvasObject
+}
+
+// DetailFactory creates the view shown by a MasterDetail for models that don't implement DetailViewer. It may return
+// nil to show the placeholder instead.
+type DetailFactory func(id widget.TreeNodeID, model TreeModel) fyne.CanvasObject
+
+// Selector is implemented by trees that a MasterDetail can be bound to, like generated trees and Tree.
+type Selector interface {
+	Select(id widget.TreeNodeID)
+}
+
+// MasterDetail shows a detail view of the selected node in a layouthelp.MultiView. It only replaces or pops the views
+// it pushed, so the MultiView may be shared with other views.
+type MasterDetail struct {
+	Factory     DetailFactory                            // Factory creates views for models that don't implement DetailViewer.
+	Placeholder fyne.CanvasObject                        // Placeholder is shown when no node is shown, or the node has no view. Nothing is shown if it's nil.
+	CanLeave    func(shown, next widget.TreeNodeID) bool // CanLeave may return false to keep the shown node, like when it has unsaved changes.
+
+	registry *TreeModelRegistry
+	unlisten func() // unlisten removes the registry listener.
+	view     *layouthelp.MultiView
+	mux      sync.Mutex
+	shown    widget.TreeNodeID
+	pushed   fyne.CanvasObject // pushed is the view MasterDetail pushed onto view, which is the only one it replaces or pops.
+}
+
+// NewMasterDetail creates a MasterDetail showing the nodes of registry in view. A new MultiView is created if view is nil.
+// Nothing is shown until Show or ShowPlaceholder is called.
+func NewMasterDetail(registry *TreeModelRegistry, view *layouthelp.MultiView) *MasterDetail {
+	if view == nil {
+		view = layouthelp.NewMultiView()
+	}
+	m := &MasterDetail{
+		registry: registry,
+		view:     view,
+	}
+	m.unlisten = registry.AddListener(m.registryChanged)
+	return m
+}
+
+// Close stops following changes to the registry, so a MasterDetail that's no longer used doesn't outlive it. The view
+// that's shown is left as it is.
+func (m *MasterDetail) Close() {
+	m.unlisten()
+}
+
+// View returns the MultiView the detail views are shown in.
+func (m *MasterDetail) View() *layouthelp.MultiView {
+	return m.view
+}
+
+// Shown returns the ID of the node being shown, or an empty ID if there isn't one.
+func (m *MasterDetail) Shown() widget.TreeNodeID {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.shown
+}
+
+// Show replaces the detail view with the view of the node with the given ID, or the placeholder if id is empty or
+// isn't registered. It returns false without changing the view if CanLeave vetoes leaving the shown node.
+func (m *MasterDetail) Show(id widget.TreeNodeID) bool {
+	model := m.registry.Node(id)
+	if model == nil {
+		id = ""
+	}
+	m.mux.Lock()
+	shown := m.shown
+	m.mux.Unlock()
+	if id == shown && id != "" {
+		return true
+	}
+	if shown != "" && m.CanLeave != nil && !m.CanLeave(shown, id) {
+		return false
+	}
+	m.mux.Lock()
+	m.shown = id
+	m.mux.Unlock()
+	m.replace(m.detailFor(id, model))
+	return true
+}
+
+// ShowPlaceholder shows the placeholder, unless CanLeave vetoes leaving the shown node.
+func (m *MasterDetail) ShowPlaceholder() bool {
+	return m.Show("")
+}
+
+// Bind shows the detail view of nodes as they're selected in tree, where onSelected is the address of the tree's
+// OnSelected field, like &tree.OnSelected. The handler already set, if any, is called after the view is shown. If
+// CanLeave vetoes a selection, the shown node is selected again instead, which is passed on to that handler too.
+func (m *MasterDetail) Bind(tree Selector, onSelected *func(id widget.TreeNodeID)) {
+	previous := *onSelected
+	*onSelected = func(id widget.TreeNodeID) {
+		if !m.Show(id) {
+			tree.Select(m.Shown())
+			return
+		}
+		if previous != nil {
+			previous(id)
+		}
+	}
+}
+
+func (m *MasterDetail) detailFor(id widget.TreeNodeID, model TreeModel) fyne.CanvasObject {
+	if model == nil {
+		return m.Placeholder
+	}
+	if viewer, ok := model.(DetailViewer); ok {
+		if view := viewer.DetailView(); view != nil {
+			return view
+		}
+	}
+	if m.Factory != nil {
+		if view := m.Factory(id, model); view != nil {
+			return view
+		}
+	}
+	return m.Placeholder
+}
+
+// replace shows obj in place of the view MasterDetail pushed, or pops that view if obj is nil. Views pushed by others
+// are never replaced or popped, so obj is pushed on top of them if MasterDetail's view isn't shown.
+func (m *MasterDetail) replace(obj fyne.CanvasObject) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	replaced := m.pushed != nil && m.view.ReplaceTop(m.pushed, obj)
+	m.pushed = obj
+	if !replaced && obj != nil {
+		m.view.Push(obj)
+	}
+}
+
+// registryChanged shows the placeholder once the shown node is removed, without asking CanLeave since there's nothing
+// left to keep.
+func (m *MasterDetail) registryChanged(event RegistryEvent) {
+	if event.Kind != NodeRemoved {
+		return
+	}
+	m.mux.Lock()
+	shown := m.shown
+	if shown == "" || m.registry.Node(shown) != nil {
+		m.mux.Unlock()
+		return
+	}
+	m.shown = ""
+	m.mux.Unlock()
+	m.replace(m.Placeholder)
+}
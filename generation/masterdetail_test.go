@@ -0,0 +1,187 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/drognisep/fynehelpers/layouthelp"
+	testify "github.com/stretchr/testify/require"
+)
+
+type detailedModel struct {
+	ModelData
+	view fyne.CanvasObject
+}
+
+func (d *detailedModel) DetailView() fyne.CanvasObject {
+	return d.view
+}
+
+func shownDetail(m *MasterDetail) fyne.CanvasObject {
+	objects := m.View().Container().Objects
+	if len(objects) == 0 {
+		return nil
+	}
+	return objects[len(objects)-1]
+}
+
+func TestMasterDetail_Show(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	detailed := &detailedModel{ModelData: ModelData{Data: "detailed"}, view: widget.NewLabel("detailed view")}
+	_, err := reg.AddChild(ModelRoot, detailed)
+	assert.NoError(err)
+	_, err = reg.AddChild(ModelRoot, &ModelData{Data: "plain"})
+	assert.NoError(err)
+	_, err = reg.AddChild(ModelRoot, &ModelData{Data: "empty"})
+	assert.NoError(err)
+
+	placeholder := widget.NewLabel("Nothing selected")
+	m := NewMasterDetail(reg, nil)
+	m.Placeholder = placeholder
+	var factoryIDs []widget.TreeNodeID
+	m.Factory = func(id widget.TreeNodeID, model TreeModel) fyne.CanvasObject {
+		factoryIDs = append(factoryIDs, id)
+		if id == "empty" {
+			return nil
+		}
+		return widget.NewLabel(model.DisplayString())
+	}
+	assert.Nil(shownDetail(m), "Nothing should be shown until asked")
+
+	assert.True(m.Show("detailed"))
+	assert.Equal(detailed.view, shownDetail(m), "DetailViewer models should provide their own view")
+	assert.Equal(widget.TreeNodeID("detailed"), m.Shown())
+	assert.Empty(factoryIDs)
+
+	assert.True(m.Show("plain"))
+	assert.Equal("plain", shownDetail(m).(*widget.Label).Text, "Other models should use the factory")
+	assert.Len(m.View().Container().Objects, 1, "The previous view should be replaced")
+
+	assert.True(m.Show("empty"))
+	assert.Equal(placeholder, shownDetail(m), "A nil view should show the placeholder")
+
+	assert.True(m.Show("missing"))
+	assert.Equal(placeholder, shownDetail(m))
+	assert.Equal(widget.TreeNodeID(""), m.Shown(), "Unregistered nodes should show nothing")
+
+	assert.True(m.Show("plain"))
+	reg.RemoveChild("plain")
+	assert.Equal(placeholder, shownDetail(m), "Removing the shown node should show the placeholder")
+	assert.Equal(widget.TreeNodeID(""), m.Shown())
+
+	m.Placeholder = nil
+	assert.True(m.Show("detailed"))
+	assert.True(m.ShowPlaceholder())
+	assert.Nil(shownDetail(m), "Without a placeholder nothing should be shown")
+}
+
+func TestMasterDetail_SharedView(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	_, err := reg.AddChild(ModelRoot, &ModelData{Data: "a"})
+	assert.NoError(err)
+
+	view := layouthelp.NewMultiView()
+	own := widget.NewLabel("own view")
+	view.Push(own)
+	m := NewMasterDetail(reg, view)
+	m.Factory = func(id widget.TreeNodeID, model TreeModel) fyne.CanvasObject {
+		return widget.NewLabel(model.DisplayString())
+	}
+	assert.True(m.ShowPlaceholder())
+	assert.Equal(own, shownDetail(m), "A nil placeholder shouldn't pop a view MasterDetail didn't push")
+
+	assert.True(m.Show("a"))
+	assert.Equal("a", shownDetail(m).(*widget.Label).Text, "The first view should be pushed")
+	assert.True(m.ShowPlaceholder())
+	assert.Equal(own, shownDetail(m), "Only the pushed view should be popped")
+
+	assert.True(m.Show("a"))
+	other := widget.NewLabel("other view")
+	view.Push(other)
+	assert.True(m.ShowPlaceholder())
+	assert.Equal(other, shownDetail(m), "Views pushed over MasterDetail's shouldn't be popped")
+}
+
+func TestMasterDetail_Close(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	_, err := reg.AddChild(ModelRoot, &ModelData{Data: "a"})
+	assert.NoError(err)
+	m := NewMasterDetail(reg, nil)
+	m.Factory = func(id widget.TreeNodeID, model TreeModel) fyne.CanvasObject {
+		return widget.NewLabel(model.DisplayString())
+	}
+	assert.True(m.Show("a"))
+
+	m.Close()
+	reg.RemoveChild("a")
+	assert.Equal(widget.TreeNodeID("a"), m.Shown(), "A closed MasterDetail shouldn't follow the registry")
+	assert.Equal("a", shownDetail(m).(*widget.Label).Text)
+}
+
+func TestMasterDetail_CanLeave(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	for _, name := range []string{"a", "b"} {
+		_, err := reg.AddChild(ModelRoot, &ModelData{Data: name})
+		assert.NoError(err)
+	}
+	m := NewMasterDetail(reg, nil)
+	m.Factory = func(id widget.TreeNodeID, model TreeModel) fyne.CanvasObject {
+		return widget.NewLabel(model.DisplayString())
+	}
+	unsaved := false
+	var asked [][2]widget.TreeNodeID
+	m.CanLeave = func(shown, next widget.TreeNodeID) bool {
+		asked = append(asked, [2]widget.TreeNodeID{shown, next})
+		return !unsaved
+	}
+
+	assert.True(m.Show("a"))
+	assert.Empty(asked, "Nothing shown shouldn't need to be left")
+	unsaved = true
+	assert.False(m.Show("b"))
+	assert.Equal(widget.TreeNodeID("a"), m.Shown())
+	assert.Equal("a", shownDetail(m).(*widget.Label).Text)
+	assert.False(m.ShowPlaceholder())
+	assert.True(m.Show("a"), "Showing the shown node again shouldn't ask to leave it")
+	assert.Equal([][2]widget.TreeNodeID{{"a", "b"}, {"a", ""}}, asked)
+
+	unsaved = false
+	assert.True(m.Show("b"))
+	assert.Equal("b", shownDetail(m).(*widget.Label).Text)
+}
+
+func TestMasterDetail_Bind(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, _ := buildTree(t)
+	m := NewMasterDetail(tree.TreeModelRegistry, nil)
+	m.Factory = func(id widget.TreeNodeID, model TreeModel) fyne.CanvasObject {
+		return widget.NewLabel(model.DisplayString())
+	}
+	var selected []widget.TreeNodeID
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		selected = append(selected, id)
+	}
+	m.Bind(tree, &tree.OnSelected)
+
+	tree.Select(ids["a"])
+	assert.Equal(ids["a"], m.Shown())
+	assert.Equal("a", shownDetail(m).(*widget.Label).Text)
+	assert.Equal([]widget.TreeNodeID{ids["a"]}, selected, "The previous handler should still be called")
+
+	m.CanLeave = func(shown, next widget.TreeNodeID) bool {
+		return false
+	}
+	tree.Select(ids["b"])
+	assert.Equal(ids["a"], m.Shown())
+	assert.Equal([]widget.TreeNodeID{ids["a"]}, tree.SelectedIDs(), "A vetoed selection should be reverted")
+	assert.Equal([]widget.TreeNodeID{ids["a"], ids["a"]}, selected, "Reselecting the shown node should be passed on")
+}
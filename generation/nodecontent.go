@@ -0,0 +1,38 @@
+package generation
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// NodeContent displays a model within a tree node, in place of the default icon and label. The node still draws the
+// selection, hover and drop highlights, checkboxes and rename entries around it.
+type NodeContent interface {
+	Objects() []fyne.CanvasObject                 // Objects returns the objects that display the model. They're created once, when the node is created, and reused as the node is bound to other models.
+	Update(id widget.TreeNodeID, model TreeModel) // Update changes the objects to display model.
+	Layout(size fyne.Size)                        // Layout positions the objects within size.
+	MinSize() fyne.Size                           // MinSize returns the smallest size that fits the objects.
+}
+
+// NodeContentFactory creates the NodeContent for a new tree node.
+type NodeContentFactory func() NodeContent
+
+// NewNodeContentContainer wraps content in a container, so it can be laid out beside other objects in a node.
+func NewNodeContentContainer(content NodeContent) *fyne.Container {
+	return container.New(&nodeContentLayout{content: content}, content.Objects()...)
+}
+
+var _ fyne.Layout = (*nodeContentLayout)(nil)
+
+type nodeContentLayout struct {
+	content NodeContent
+}
+
+func (l *nodeContentLayout) Layout(_ []fyne.CanvasObject, size fyne.Size) {
+	l.content.Layout(size)
+}
+
+func (l *nodeContentLayout) MinSize(_ []fyne.CanvasObject) fyne.Size {
+	return l.content.MinSize()
+}
@@ -0,0 +1,71 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+// progressContent shows a model as a label over a progress bar.
+type progressContent struct {
+	label    *widget.Label
+	progress *widget.ProgressBar
+	laidOut  fyne.Size
+}
+
+func newProgressContent() NodeContent {
+	return &progressContent{
+		label:    widget.NewLabel(""),
+		progress: widget.NewProgressBar(),
+	}
+}
+
+func (c *progressContent) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{c.progress, c.label}
+}
+
+func (c *progressContent) Update(id widget.TreeNodeID, model TreeModel) {
+	c.label.SetText(id + ": " + model.DisplayString())
+	c.progress.SetValue(float64(len(model.DisplayString())) / 10)
+}
+
+func (c *progressContent) Layout(size fyne.Size) {
+	c.laidOut = size
+	c.progress.Resize(size)
+	c.label.Resize(size)
+}
+
+func (c *progressContent) MinSize() fyne.Size {
+	return c.label.MinSize().Max(fyne.NewSize(100, 0))
+}
+
+func TestNewNodeContentContainer(t *testing.T) {
+	assert := testify.New(t)
+	content := newProgressContent().(*progressContent)
+	box := NewNodeContentContainer(content)
+	assert.Equal(content.Objects(), box.Objects)
+	assert.Equal(content.MinSize(), box.MinSize())
+	box.Resize(fyne.NewSize(200, 30))
+	assert.Equal(fyne.NewSize(200, 30), content.laidOut, "The container should lay out the content")
+}
+
+func TestTree_NodeContent(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, w := buildTree(t, WithNodeContent(newProgressContent), WithRename())
+	defer w.Close()
+
+	node := displayedNode(t, tree, ids["a1"])
+	content := node.render.custom.(*progressContent)
+	assert.Equal("root/a/a1: a1", content.label.Text)
+	assert.Equal(0.2, content.progress.Value)
+	assert.Equal(node.Size().Width, content.laidOut.Width, "Custom content should fill the node")
+
+	renamed := displayedNode(t, tree, ids["a"])
+	assert.NoError(tree.StartRename(ids["a"]))
+	assert.False(renamed.render.customBox.Visible(), "The entry should replace custom content while renaming")
+	assert.True(renamed.render.entry.Visible())
+	tree.stopRename()
+	assert.True(renamed.render.customBox.Visible())
+}
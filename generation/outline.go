@@ -0,0 +1,292 @@
+package generation
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/pkg/errors"
+)
+
+var _ RenamableTreeModel = (*OutlineItem)(nil)
+
+// OutlineFactory creates the model for a label read from an outline.
+type OutlineFactory func(label string) (TreeModel, error)
+
+// OutlineItem is a renamable model holding a label, used for imported outlines when no OutlineFactory is given.
+type OutlineItem struct {
+	BaseTreeModel
+
+	mux   sync.RWMutex
+	label string
+}
+
+// NewOutlineItem creates an item labelled label.
+func NewOutlineItem(label string) *OutlineItem {
+	return &OutlineItem{label: label}
+}
+
+func (i *OutlineItem) DisplayString() string {
+	i.mux.RLock()
+	defer i.mux.RUnlock()
+	return i.label
+}
+
+func (i *OutlineItem) SetDisplayString(label string) error {
+	i.mux.Lock()
+	i.label = label
+	i.mux.Unlock()
+	i.NotifyChanged()
+	return nil
+}
+
+// outline is a label and its nested labels, which every format is read into and written from.
+type outline struct {
+	label    string
+	children []*outline
+}
+
+// WriteMarkdown writes the nodes under parentID to w as a nested Markdown list, indented by two spaces per level.
+// Use ModelRoot to write the whole tree. Lazy nodes are loaded, so everything under parentID is written. Spaces around
+// labels aren't kept, since Markdown ignores them.
+func (r *TreeModelRegistry) WriteMarkdown(w io.Writer, parentID widget.TreeNodeID) error {
+	return writeIndented(w, r.outlines(parentID), "  ", func(label string) string {
+		return "- " + strings.TrimSpace(label)
+	})
+}
+
+// ReadMarkdown adds a node under parentID for each item of the nested Markdown lists in rd, keeping their order and
+// nesting, and returns the IDs of the top level nodes. Items may be bulleted or numbered, and lines that aren't list
+// items, like headings, are skipped. Models are created by factory, or are OutlineItem values if it's nil.
+func (r *TreeModelRegistry) ReadMarkdown(rd io.Reader, parentID widget.TreeNodeID, factory OutlineFactory) ([]widget.TreeNodeID, error) {
+	outlines, err := readIndented(rd, func(line string) (string, bool) {
+		match := markdownItem.FindStringSubmatch(line)
+		if match == nil {
+			return "", false
+		}
+		return strings.TrimSpace(match[1]), true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.addOutlines(parentID, outlines, factory)
+}
+
+var markdownItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])(?:\s+(.*))?$`)
+
+// WriteText writes the nodes under parentID to w as plain text, with a line for each node indented by a tab per
+// level. Use ModelRoot to write the whole tree. Lazy nodes are loaded, so everything under parentID is written. Labels
+// that are empty or start with a space, tab or backslash are written after a backslash, so they aren't read back as
+// blank lines or indentation. Trailing spaces aren't kept.
+func (r *TreeModelRegistry) WriteText(w io.Writer, parentID widget.TreeNodeID) error {
+	return writeIndented(w, r.outlines(parentID), "\t", func(label string) string {
+		label = strings.TrimRight(label, " \t")
+		if label == "" || strings.ContainsAny(label[:1], " \t\\") {
+			return `\` + label
+		}
+		return label
+	})
+}
+
+// ReadText adds a node under parentID for each line of indented text in rd, nested by indentation and keeping their
+// order, and returns the IDs of the top level nodes. Blank lines are skipped, a tab counts as four spaces, and a
+// backslash starting a label is removed, so it can be empty or start with spaces. Models are created by factory, or
+// are OutlineItem values if it's nil.
+func (r *TreeModelRegistry) ReadText(rd io.Reader, parentID widget.TreeNodeID, factory OutlineFactory) ([]widget.TreeNodeID, error) {
+	outlines, err := readIndented(rd, func(line string) (string, bool) {
+		return strings.TrimPrefix(strings.TrimSpace(line), `\`), true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.addOutlines(parentID, outlines, factory)
+}
+
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    struct {
+		Title string `xml:"title,omitempty"`
+	} `xml:"head"`
+	Body struct {
+		Outlines []opmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// WriteOPML writes the nodes under parentID to w as an OPML 2.0 document with title, using each node's display
+// string as the text of its outline element. Use ModelRoot to write the whole tree. Lazy nodes are loaded, so
+// everything under parentID is written.
+func (r *TreeModelRegistry) WriteOPML(w io.Writer, parentID widget.TreeNodeID, title string) error {
+	doc := opmlDocument{Version: "2.0"}
+	doc.Head.Title = title
+	var convert func(outlines []*outline) []opmlOutline
+	convert = func(outlines []*outline) []opmlOutline {
+		var converted []opmlOutline
+		for _, o := range outlines {
+			converted = append(converted, opmlOutline{Text: o.label, Outlines: convert(o.children)})
+		}
+		return converted
+	}
+	doc.Body.Outlines = convert(r.outlines(parentID))
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadOPML adds a node under parentID for each outline element in the body of the OPML document in rd, keeping their
+// order and nesting, and returns the IDs of the top level nodes. Labels are taken from the text attribute, or the
+// title attribute if there's no text. Models are created by factory, or are OutlineItem values if it's nil.
+func (r *TreeModelRegistry) ReadOPML(rd io.Reader, parentID widget.TreeNodeID, factory OutlineFactory) ([]widget.TreeNodeID, error) {
+	var doc opmlDocument
+	if err := xml.NewDecoder(rd).Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "failed to read OPML")
+	}
+	var convert func(outlines []opmlOutline) []*outline
+	convert = func(outlines []opmlOutline) []*outline {
+		var converted []*outline
+		for _, o := range outlines {
+			label := o.Text
+			if label == "" {
+				label = o.Title
+			}
+			converted = append(converted, &outline{label: label, children: convert(o.Outlines)})
+		}
+		return converted
+	}
+	return r.addOutlines(parentID, convert(doc.Body.Outlines), factory)
+}
+
+// outlines reads the labels of the nodes under parentID.
+func (r *TreeModelRegistry) outlines(parentID widget.TreeNodeID) []*outline {
+	r.Load(parentID)
+	var outlines []*outline
+	for _, id := range r.Children(parentID) {
+		outlines = append(outlines, &outline{
+			label:    r.Node(id).DisplayString(),
+			children: r.outlines(id),
+		})
+	}
+	return outlines
+}
+
+// addOutlines creates and adds models for outlines under parentID, returning the IDs of the top level nodes. If any
+// can't be created or added, the nodes added so far are removed again.
+func (r *TreeModelRegistry) addOutlines(parentID widget.TreeNodeID, outlines []*outline, factory OutlineFactory) ([]widget.TreeNodeID, error) {
+	if factory == nil {
+		factory = func(label string) (TreeModel, error) {
+			return NewOutlineItem(label), nil
+		}
+	}
+	var add func(parentID widget.TreeNodeID, o *outline) (widget.TreeNodeID, error)
+	add = func(parentID widget.TreeNodeID, o *outline) (widget.TreeNodeID, error) {
+		model, err := factory(o.label)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create a model for '%s'", o.label)
+		}
+		id, err := r.AddChild(parentID, model)
+		if err != nil {
+			return "", err
+		}
+		for _, child := range o.children {
+			if _, err := add(id, child); err != nil {
+				return id, err
+			}
+		}
+		return id, nil
+	}
+	var added []widget.TreeNodeID
+	for _, o := range outlines {
+		id, err := add(parentID, o)
+		if id != "" {
+			added = append(added, id)
+		}
+		if err != nil {
+			for i := len(added) - 1; i >= 0; i-- {
+				r.RemoveChild(added[i])
+			}
+			return nil, err
+		}
+	}
+	return added, nil
+}
+
+// writeIndented writes a line for each outline, starting with indent repeated for each level of nesting and then the
+// outline's label as formatted by line. Line breaks in labels are replaced with spaces.
+func writeIndented(w io.Writer, outlines []*outline, indent string, line func(label string) string) error {
+	bw := bufio.NewWriter(w)
+	var write func(outlines []*outline, depth int)
+	write = func(outlines []*outline, depth int) {
+		for _, o := range outlines {
+			_, _ = bw.WriteString(strings.Repeat(indent, depth) + line(lineBreaks.Replace(o.label)) + "\n")
+			write(o.children, depth+1)
+		}
+	}
+	write(outlines, 0)
+	return bw.Flush()
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// readIndented reads outlines from lines nested by their indentation, with each line nested under the last one that
+// was indented less. label returns the label for a line, and false if it should be skipped.
+func readIndented(rd io.Reader, label func(line string) (string, bool)) ([]*outline, error) {
+	type level struct {
+		indent  int
+		outline *outline
+	}
+	root := &outline{}
+	stack := []level{{indent: -1, outline: root}}
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		text, ok := label(line)
+		if !ok {
+			continue
+		}
+		indent := indentWidth(line)
+		for stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].outline
+		o := &outline{label: text}
+		parent.children = append(parent.children, o)
+		stack = append(stack, level{indent: indent, outline: o})
+	}
+	return root.children, scanner.Err()
+}
+
+// indentWidth counts the leading spaces of line, with a tab counting as four.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
@@ -0,0 +1,172 @@
+package generation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/pkg/errors"
+	testify "github.com/stretchr/testify/require"
+)
+
+// outlineLabels lists the labels under parentID, indented by a space per level.
+func outlineLabels(reg *TreeModelRegistry, parentID widget.TreeNodeID) []string {
+	var labels []string
+	var list func(id widget.TreeNodeID, depth int)
+	list = func(id widget.TreeNodeID, depth int) {
+		for _, child := range reg.Children(id) {
+			labels = append(labels, strings.Repeat(" ", depth)+reg.Node(child).DisplayString())
+			list(child, depth+1)
+		}
+	}
+	list(parentID, 0)
+	return labels
+}
+
+func TestTreeModelRegistry_Markdown(t *testing.T) {
+	assert := testify.New(t)
+	reg, _ := buildTopLevelRegistry(t)
+	var buf bytes.Buffer
+	assert.NoError(reg.WriteMarkdown(&buf, ModelRoot))
+	assert.Equal("- a\n  - a1\n  - a2\n- b\n", buf.String())
+
+	imported := NewTreeModelRegistry()
+	ids, err := imported.ReadMarkdown(&buf, ModelRoot, nil)
+	assert.NoError(err)
+	assert.Len(ids, 2)
+	assert.Equal(outlineLabels(reg, ModelRoot), outlineLabels(imported, ModelRoot), "Round tripping should keep order and nesting")
+	assert.IsType(&OutlineItem{}, imported.Node(ids[0]))
+
+	input := `# Heading
+
+1. First
+    * Nested
+        + Deeper
+
+    * Second nested
+2) Second
+-
+Paragraph text
+`
+	imported = NewTreeModelRegistry()
+	_, err = imported.ReadMarkdown(strings.NewReader(input), ModelRoot, nil)
+	assert.NoError(err)
+	assert.Equal([]string{"First", " Nested", "  Deeper", " Second nested", "Second", ""}, outlineLabels(imported, ModelRoot))
+}
+
+func TestTreeModelRegistry_Text(t *testing.T) {
+	assert := testify.New(t)
+	reg, _ := buildTopLevelRegistry(t)
+	var buf bytes.Buffer
+	assert.NoError(reg.WriteText(&buf, ModelRoot))
+	assert.Equal("a\n\ta1\n\ta2\nb\n", buf.String())
+
+	aID := reg.Children(ModelRoot)[0]
+	ids, err := reg.ReadText(&buf, aID, func(label string) (TreeModel, error) {
+		return &ModelData{Data: strings.ToUpper(label)}, nil
+	})
+	assert.NoError(err)
+	assert.Len(ids, 2)
+	assert.Equal([]string{"a1", "a2", "A", " A1", " A2", "B"}, outlineLabels(reg, aID), "Imported nodes should be added after existing children")
+
+	buf.Reset()
+	assert.NoError(reg.WriteText(&buf, reg.Children(aID)[2]))
+	assert.Equal("A1\nA2\n", buf.String(), "Only the nodes under the parent should be written")
+
+	imported := NewTreeModelRegistry()
+	_, err = imported.ReadText(strings.NewReader("a\n    b\n\tc\n  d\ne\r\n"), ModelRoot, nil)
+	assert.NoError(err)
+	assert.Equal([]string{"a", " b", " c", " d", "e"}, outlineLabels(imported, ModelRoot), "Tabs should count as four spaces, and lines nest under the last line indented less")
+
+	multiline := NewTreeModelRegistry()
+	_, err = multiline.AddChild(ModelRoot, NewOutlineItem("two\nlines"))
+	assert.NoError(err)
+	buf.Reset()
+	assert.NoError(multiline.WriteText(&buf, ModelRoot))
+	assert.Equal("two lines\n", buf.String())
+}
+
+func TestTreeModelRegistry_TextEscapes(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	empty := NewOutlineItem("")
+	assert.NoError(empty.AddChild(NewOutlineItem("  indented")))
+	assert.NoError(empty.AddChild(NewOutlineItem(`\path`)))
+	_, err := reg.AddChild(ModelRoot, empty)
+	assert.NoError(err)
+	_, err = reg.AddChild(ModelRoot, NewOutlineItem("trailing  "))
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(reg.WriteText(&buf, ModelRoot))
+	assert.Equal("\\\n\t\\  indented\n\t\\\\path\ntrailing\n", buf.String())
+
+	imported := NewTreeModelRegistry()
+	_, err = imported.ReadText(&buf, ModelRoot, nil)
+	assert.NoError(err)
+	assert.Equal([]string{"", "   indented", ` \path`, "trailing"}, outlineLabels(imported, ModelRoot), "Empty labels and leading spaces should be kept")
+
+	buf.Reset()
+	assert.NoError(reg.WriteMarkdown(&buf, ModelRoot))
+	imported = NewTreeModelRegistry()
+	_, err = imported.ReadMarkdown(&buf, ModelRoot, nil)
+	assert.NoError(err)
+	assert.Equal([]string{"", " indented", ` \path`, "trailing"}, outlineLabels(imported, ModelRoot), "Empty labels should be kept in Markdown")
+}
+
+func TestTreeModelRegistry_OPML(t *testing.T) {
+	assert := testify.New(t)
+	reg, _ := buildTopLevelRegistry(t)
+	var buf bytes.Buffer
+	assert.NoError(reg.WriteOPML(&buf, ModelRoot, "My outline"))
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>My outline</title>
+  </head>
+  <body>
+    <outline text="a">
+      <outline text="a1"></outline>
+      <outline text="a2"></outline>
+    </outline>
+    <outline text="b"></outline>
+  </body>
+</opml>
+`, buf.String())
+
+	imported := NewTreeModelRegistry()
+	_, err := imported.ReadOPML(&buf, ModelRoot, nil)
+	assert.NoError(err)
+	assert.Equal(outlineLabels(reg, ModelRoot), outlineLabels(imported, ModelRoot))
+
+	imported = NewTreeModelRegistry()
+	_, err = imported.ReadOPML(strings.NewReader(`<opml version="1.0"><body><outline title="Titled"><outline text="A &amp; B"/></outline></body></opml>`), ModelRoot, nil)
+	assert.NoError(err)
+	assert.Equal([]string{"Titled", " A & B"}, outlineLabels(imported, ModelRoot), "The title should be used without text")
+
+	_, err = imported.ReadOPML(strings.NewReader(`<rss/>`), ModelRoot, nil)
+	assert.Error(err)
+}
+
+func TestTreeModelRegistry_ReadRollback(t *testing.T) {
+	assert := testify.New(t)
+	reg, _ := buildTopLevelRegistry(t)
+	errFactory := errors.New("factory failed")
+	_, err := reg.ReadText(strings.NewReader("a\n\tb\nc\n\tfail\n"), ModelRoot, func(label string) (TreeModel, error) {
+		if label == "fail" {
+			return nil, errFactory
+		}
+		return NewOutlineItem(label), nil
+	})
+	assert.ErrorIs(err, errFactory)
+	assert.Equal([]string{"a", " a1", " a2", "b"}, outlineLabels(reg, ModelRoot), "Nodes added before the error should be removed")
+
+	lazy := &lazyModel{ModelData: ModelData{Data: "lazy"}}
+	assert.NoError(lazy.AddChild(&ModelData{Data: "child"}))
+	_, err = reg.AddChild(ModelRoot, lazy)
+	assert.NoError(err)
+	var buf bytes.Buffer
+	assert.NoError(reg.WriteMarkdown(&buf, ModelRoot))
+	assert.Contains(buf.String(), "- lazy\n  - child\n", "Lazy nodes should be loaded to be written")
+}
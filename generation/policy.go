@@ -0,0 +1,95 @@
+package generation
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Unlimited is returned by ChildPolicy.MaxChildren, and set as ChildRules.Max, when there's no limit on the number of
+// children.
+const Unlimited = -1
+
+// ChildPolicy may be implemented by a TreeModel to declare which children it will accept.
+// TreeModelRegistry consults the policy before adding a child, so the same rules apply to drag and drop, paste, and
+// programmatic additions.
+type ChildPolicy interface {
+	CanAccept(child TreeModel) bool // CanAccept is checked after the other constraints and has the final say on whether the child is accepted.
+	MaxChildren() int               // MaxChildren returns the most children that may be held. Return Unlimited for no limit, or 0 for a leaf.
+	AllowedTypes() []TreeModel      // AllowedTypes returns an example value for each type of child that may be added. Return nil to allow any type.
+}
+
+// ErrRejected is returned when a parent's ChildPolicy refuses a new child.
+type ErrRejected struct {
+	Parent TreeModel
+	Child  TreeModel
+	Reason string
+}
+
+func (e *ErrRejected) Error() string {
+	return fmt.Sprintf("child rejected: %s", e.Reason)
+}
+
+// CheckChildPolicy returns an *ErrRejected if parent implements ChildPolicy and won't accept child.
+// This can be used to check an operation like a drop before attempting it.
+func CheckChildPolicy(parent TreeModel, child TreeModel) error {
+	policy, ok := parent.(ChildPolicy)
+	if !ok {
+		return nil
+	}
+	reject := func(format string, args ...interface{}) error {
+		return &ErrRejected{
+			Parent: parent,
+			Child:  child,
+			Reason: fmt.Sprintf(format, args...),
+		}
+	}
+
+	max := policy.MaxChildren()
+	if max == 0 {
+		return reject("parent does not accept children")
+	}
+	if count := len(parent.Children()); max > 0 && count >= max {
+		return reject("parent already has the maximum of %d children", max)
+	}
+	if allowed := policy.AllowedTypes(); len(allowed) > 0 {
+		childType := reflect.TypeOf(child)
+		var found bool
+		for _, example := range allowed {
+			if reflect.TypeOf(example) == childType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reject("type %T is not allowed", child)
+		}
+	}
+	if !policy.CanAccept(child) {
+		return reject("refused by parent")
+	}
+	return nil
+}
+
+var _ ChildPolicy = (*ChildRules)(nil)
+
+// ChildRules is a declarative ChildPolicy that may be embedded in a model.
+type ChildRules struct {
+	Leaf    bool        // Leaf rejects all children.
+	Max     int         // Max is the most children allowed, like ChildPolicy.MaxChildren. Set it to Unlimited for no limit, since 0 allows none.
+	Allowed []TreeModel // Allowed holds an example value for each type of child that may be added. Empty allows any type.
+}
+
+func (c *ChildRules) CanAccept(TreeModel) bool {
+	return true
+}
+
+func (c *ChildRules) MaxChildren() int {
+	if c.Leaf {
+		return 0
+	}
+	return c.Max
+}
+
+func (c *ChildRules) AllowedTypes() []TreeModel {
+	return c.Allowed
+}
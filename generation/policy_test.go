@@ -0,0 +1,136 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	testify "github.com/stretchr/testify/require"
+)
+
+type policyFolder struct {
+	BaseTreeModel
+	ChildRules
+}
+
+type policyFile struct {
+	BaseTreeModel
+	ChildRules
+}
+
+type pickyModel struct {
+	BaseTreeModel
+}
+
+func (p *pickyModel) CanAccept(child TreeModel) bool {
+	data, ok := child.(*ModelData)
+	return ok && data.Data != "nope"
+}
+
+func (p *pickyModel) MaxChildren() int {
+	return Unlimited
+}
+
+func (p *pickyModel) AllowedTypes() []TreeModel {
+	return nil
+}
+
+func newPolicyFolder() *policyFolder {
+	return &policyFolder{
+		ChildRules: ChildRules{
+			Max:     Unlimited,
+			Allowed: []TreeModel{(*policyFolder)(nil), (*policyFile)(nil)},
+		},
+	}
+}
+
+func newPolicyFile() *policyFile {
+	return &policyFile{
+		ChildRules: ChildRules{Leaf: true},
+	}
+}
+
+func TestCheckChildPolicy(t *testing.T) {
+	limited := &policyFolder{ChildRules: ChildRules{Max: 1}}
+	if err := limited.AddChild(newPolicyFile()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		parent   TreeModel
+		child    TreeModel
+		rejected bool
+	}{
+		"No policy": {
+			parent: &BaseTreeModel{},
+			child:  newPolicyFile(),
+		},
+		"Folder accepts file": {
+			parent: newPolicyFolder(),
+			child:  newPolicyFile(),
+		},
+		"Folder accepts folder": {
+			parent: newPolicyFolder(),
+			child:  newPolicyFolder(),
+		},
+		"Folder rejects other types": {
+			parent:   newPolicyFolder(),
+			child:    &ModelData{},
+			rejected: true,
+		},
+		"File rejects everything": {
+			parent:   newPolicyFile(),
+			child:    newPolicyFile(),
+			rejected: true,
+		},
+		"Max of zero rejects everything": {
+			parent:   &policyFolder{},
+			child:    newPolicyFile(),
+			rejected: true,
+		},
+		"Max children reached": {
+			parent:   limited,
+			child:    newPolicyFile(),
+			rejected: true,
+		},
+		"CanAccept allows": {
+			parent: &pickyModel{},
+			child:  &ModelData{Data: "yes"},
+		},
+		"CanAccept refuses": {
+			parent:   &pickyModel{},
+			child:    &ModelData{Data: "nope"},
+			rejected: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := testify.New(t)
+			err := CheckChildPolicy(tc.parent, tc.child)
+			if !tc.rejected {
+				assert.NoError(err)
+				return
+			}
+			var rejected *ErrRejected
+			assert.True(errors.As(err, &rejected), "Error should be an *ErrRejected")
+			assert.NotEmpty(rejected.Reason)
+			assert.Equal(tc.parent, rejected.Parent)
+			assert.Equal(tc.child, rejected.Child)
+		})
+	}
+}
+
+func TestTreeModelRegistry_AddChild_Rejected(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+
+	file := newPolicyFile()
+	fileID, err := reg.AddChild(ModelRoot, file)
+	assert.NoError(err, "The root has no policy")
+
+	_, err = reg.AddChild(fileID, newPolicyFile())
+	var rejected *ErrRejected
+	assert.True(errors.As(err, &rejected))
+	assert.Len(file.Children(), 0, "The model should not be changed when rejected")
+	assert.False(reg.HasChildren(fileID), "Nothing should be registered when rejected")
+}
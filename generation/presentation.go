@@ -0,0 +1,69 @@
+package generation
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+)
+
+// SubtitledTreeModel may be implemented by a TreeModel to show secondary text beside its label.
+type SubtitledTreeModel interface {
+	DisplaySubtitle() string // DisplaySubtitle returns secondary text shown after the label. Return an empty string for no subtitle.
+}
+
+// StyledTreeModel may be implemented by a TreeModel to change the style of its label.
+type StyledTreeModel interface {
+	DisplayTextStyle() fyne.TextStyle // DisplayTextStyle returns the style of the node's label, e.g. bold or monospace.
+}
+
+// ColoredTreeModel may be implemented by a TreeModel to change the color of its label.
+type ColoredTreeModel interface {
+	DisplayColor() color.Color // DisplayColor returns the color of the node's label. Return nil to use the theme's text color.
+}
+
+// BadgedTreeModel may be implemented by a TreeModel to show a count at the trailing end of the node.
+type BadgedTreeModel interface {
+	DisplayBadge() int // DisplayBadge returns the count shown in the node's badge. Return 0 to hide the badge.
+}
+
+// DescribedTreeModel may be implemented by a TreeModel to provide a longer description, shown like a tooltip.
+type DescribedTreeModel interface {
+	DisplayDescription() string // DisplayDescription returns the node's description. Return an empty string for no description.
+}
+
+// NodePresentation gathers everything a node renderer needs to display a TreeModel, including values from the optional
+// presentation interfaces.
+type NodePresentation struct {
+	Icon        fyne.Resource
+	Text        string
+	Subtitle    string
+	TextStyle   fyne.TextStyle
+	TextColor   color.Color
+	Badge       int
+	Description string
+}
+
+// PresentationOf reads the presentation of model, leaving fields for unimplemented optional interfaces at their zero
+// value.
+func PresentationOf(model TreeModel) NodePresentation {
+	p := NodePresentation{
+		Icon: model.DisplayIcon(),
+		Text: model.DisplayString(),
+	}
+	if m, ok := model.(SubtitledTreeModel); ok {
+		p.Subtitle = m.DisplaySubtitle()
+	}
+	if m, ok := model.(StyledTreeModel); ok {
+		p.TextStyle = m.DisplayTextStyle()
+	}
+	if m, ok := model.(ColoredTreeModel); ok {
+		p.TextColor = m.DisplayColor()
+	}
+	if m, ok := model.(BadgedTreeModel); ok {
+		p.Badge = m.DisplayBadge()
+	}
+	if m, ok := model.(DescribedTreeModel); ok {
+		p.Description = m.DisplayDescription()
+	}
+	return p
+}
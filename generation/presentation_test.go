@@ -0,0 +1,56 @@
+package generation
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	testify "github.com/stretchr/testify/require"
+)
+
+type presentedModel struct {
+	ModelData
+}
+
+func (p *presentedModel) DisplayIcon() fyne.Resource {
+	return theme.FolderIcon()
+}
+
+func (p *presentedModel) DisplaySubtitle() string {
+	return "subtitle"
+}
+
+func (p *presentedModel) DisplayTextStyle() fyne.TextStyle {
+	return fyne.TextStyle{Bold: true}
+}
+
+func (p *presentedModel) DisplayColor() color.Color {
+	return color.NRGBA{R: 0xff, A: 0xff}
+}
+
+func (p *presentedModel) DisplayBadge() int {
+	return 3
+}
+
+func (p *presentedModel) DisplayDescription() string {
+	return "description"
+}
+
+func TestPresentationOf(t *testing.T) {
+	assert := testify.New(t)
+
+	plain := PresentationOf(&ModelData{Data: "plain"})
+	assert.Equal(NodePresentation{Text: "plain"}, plain, "Optional fields should be left at their zero value")
+
+	rich := PresentationOf(&presentedModel{ModelData: ModelData{Data: "rich"}})
+	assert.Equal(NodePresentation{
+		Icon:        theme.FolderIcon(),
+		Text:        "rich",
+		Subtitle:    "subtitle",
+		TextStyle:   fyne.TextStyle{Bold: true},
+		TextColor:   color.NRGBA{R: 0xff, A: 0xff},
+		Badge:       3,
+		Description: "description",
+	}, rich)
+}
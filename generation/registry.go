@@ -2,10 +2,10 @@ package generation
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
 
 	"fyne.io/fyne/v2/widget"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
@@ -15,7 +15,9 @@ const (
 
 var (
 	ErrNoSuchParent = errors.New("no such parent exists")
+	ErrNoSuchNode   = errors.New("no such node exists")
 	ErrNilData      = errors.New("nil data")
+	ErrInvalidMove  = errors.New("a node can't be moved into itself or its descendants")
 )
 
 type modelIdMap = map[widget.TreeNodeID]TreeModel
@@ -27,6 +29,44 @@ type TreeModelRegistry struct {
 	idMap     modelIdMap
 	childMap  modelChildMap
 	parentMap modelParentMap
+	unloaded  map[widget.TreeNodeID]bool   // unloaded holds lazy nodes whose children haven't been registered.
+	observers map[widget.TreeNodeID]func() // observers holds the functions that stop observing each ObservableTreeModel.
+
+	idStrategy IDStrategy
+
+	listenerMux sync.RWMutex
+	listeners   []*registryListener
+
+	heldMux sync.Mutex
+	holding bool                // holding is set while the registry is locked for writing, see lock.
+	held    []widget.TreeNodeID // held lists the nodes that models reported as updated while holding.
+}
+
+// RegistryEventKind identifies the kind of change made to a TreeModelRegistry.
+type RegistryEventKind int
+
+const (
+	NodeAdded   RegistryEventKind = iota // NodeAdded is sent when a node, and any children it already has, is registered.
+	NodeRemoved                          // NodeRemoved is sent when a node and its children are deregistered.
+	NodeUpdated                          // NodeUpdated is sent when a node's model changes in a way that should be displayed.
+	NodeMoved                            // NodeMoved is sent when a node and its children are moved to a new parent or position.
+	NodeLoaded                           // NodeLoaded is sent when the children of a LazyTreeModel are registered, see TreeModelRegistry.Load.
+)
+
+// RegistryEvent describes a change to a TreeModelRegistry.
+type RegistryEvent struct {
+	Kind             RegistryEventKind
+	ID               widget.TreeNodeID
+	ParentID         widget.TreeNodeID
+	PreviousParentID widget.TreeNodeID // PreviousParentID is only set for NodeMoved.
+}
+
+// RegistryListener is notified of changes to a TreeModelRegistry. Listeners are called after the change is complete, so
+// they're free to use the registry.
+type RegistryListener = func(event RegistryEvent)
+
+type registryListener struct {
+	listener RegistryListener
 }
 
 func NewTreeModelRegistry() *TreeModelRegistry {
@@ -34,25 +74,42 @@ func NewTreeModelRegistry() *TreeModelRegistry {
 		idMap:     modelIdMap{},
 		childMap:  modelChildMap{},
 		parentMap: modelParentMap{},
+		unloaded:  map[widget.TreeNodeID]bool{},
+		observers: map[widget.TreeNodeID]func(){},
 	}
 	reg.idMap[ModelRoot] = nil
 	return reg
 }
 
 func (r *TreeModelRegistry) AddChild(parentID widget.TreeNodeID, data TreeModel) (widget.TreeNodeID, error) {
-	r.mux.Lock()
-	defer r.mux.Unlock()
+	r.Load(parentID)
+	r.lock()
+	dataID, err := r.addChild(parentID, data)
+	held := r.unlock()
+	if err == nil {
+		r.notify(RegistryEvent{Kind: NodeAdded, ID: dataID, ParentID: parentID})
+	}
+	r.sendHeld(held)
+	return dataID, err
+}
+
+func (r *TreeModelRegistry) addChild(parentID widget.TreeNodeID, data TreeModel) (widget.TreeNodeID, error) {
 	parentNode, ok := r.idMap[parentID]
 	if !ok {
 		return "", ErrNoSuchParent
 	}
-	if data == nil {
+	if isNilModel(data) {
 		return "", ErrNilData
 	}
+	if parentNode != nil {
+		if err := CheckChildPolicy(parentNode, data); err != nil {
+			return "", err
+		}
+	}
 	if err := r.propagateAdd(parentNode, data); err != nil {
 		return "", err
 	}
-	dataID := r.getID()
+	dataID := r.getID(parentID, data)
 	r.buildParentLinkage(parentID, data, dataID)
 	return dataID, nil
 }
@@ -70,25 +127,37 @@ func (r *TreeModelRegistry) buildParentLinkage(parentID widget.TreeNodeID, child
 	r.idMap[childID] = child
 	r.childMap[parentID] = append(r.childMap[parentID], childID)
 	r.parentMap[childID] = parentID
+	if observable, ok := child.(ObservableTreeModel); ok {
+		r.observers[childID] = observable.AddChangeListener(func() {
+			r.modelChanged(childID)
+		})
+	}
 	r.buildExtendedLinkage(childID, child)
 }
 
 func (r *TreeModelRegistry) buildExtendedLinkage(parentID widget.TreeNodeID, parent TreeModel) {
+	if _, ok := parent.(LazyTreeModel); ok {
+		r.unloaded[parentID] = true
+		return
+	}
 	for _, c := range parent.Children() {
-		cid := r.getID()
+		cid := r.getID(parentID, c)
 		r.buildParentLinkage(parentID, c, cid)
 	}
 }
 
 func (r *TreeModelRegistry) RemoveChild(dataID widget.TreeNodeID) {
-	r.mux.Lock()
-	defer r.mux.Unlock()
+	r.lock()
 	parentID, ok := r.parentMap[dataID]
 	if !ok {
+		r.unlock()
 		return
 	}
 	r.propagateRemove(r.idMap[parentID], r.idMap[dataID])
 	r.tearDownParentLinkage(parentID, dataID)
+	held := r.unlock()
+	r.notify(RegistryEvent{Kind: NodeRemoved, ID: dataID, ParentID: parentID})
+	r.sendHeld(held)
 }
 
 func (r *TreeModelRegistry) propagateRemove(parent TreeModel, child TreeModel) {
@@ -103,14 +172,14 @@ func (r *TreeModelRegistry) propagateRemove(parent TreeModel, child TreeModel) {
 
 func (r *TreeModelRegistry) tearDownParentLinkage(parentID widget.TreeNodeID, childID widget.TreeNodeID) {
 	curChildren := r.childMap[parentID]
-	for i, cid := range curChildren {
-		if cid == childID {
-			r.childMap[parentID] = append(curChildren[:i], curChildren[i+1:]...)
-			break
-		}
-	}
+	r.childMap[parentID] = removeID(curChildren, indexOfID(curChildren, childID))
 	r.tearDownExtendedLinkage(childID)
+	if stopObserving, ok := r.observers[childID]; ok {
+		stopObserving()
+		delete(r.observers, childID)
+	}
 	delete(r.idMap, childID)
+	delete(r.unloaded, childID)
 	if len(r.childMap[parentID]) == 0 {
 		delete(r.childMap, parentID)
 	}
@@ -118,11 +187,81 @@ func (r *TreeModelRegistry) tearDownParentLinkage(parentID widget.TreeNodeID, ch
 }
 
 func (r *TreeModelRegistry) tearDownExtendedLinkage(parentID widget.TreeNodeID) {
-	for _, cid := range r.childMap[parentID] {
+	// Tearing down a child modifies the parent's child list, so iterate over a copy.
+	children := make([]widget.TreeNodeID, len(r.childMap[parentID]))
+	copy(children, r.childMap[parentID])
+	for _, cid := range children {
 		r.tearDownParentLinkage(parentID, cid)
 	}
 }
 
+// MoveChild moves a registered node, along with its children, to index within newParentID's children. The moved nodes
+// keep their IDs. An index that's negative or past the end appends the node.
+// The move is propagated to the parent models, and is checked against the new parent's ChildPolicy if the parent
+// changes.
+func (r *TreeModelRegistry) MoveChild(nodeID widget.TreeNodeID, newParentID widget.TreeNodeID, index int) error {
+	r.Load(newParentID)
+	r.lock()
+	prevParentID, err := r.moveChild(nodeID, newParentID, index)
+	held := r.unlock()
+	if err == nil {
+		r.notify(RegistryEvent{Kind: NodeMoved, ID: nodeID, ParentID: newParentID, PreviousParentID: prevParentID})
+	}
+	r.sendHeld(held)
+	return err
+}
+
+func (r *TreeModelRegistry) moveChild(nodeID widget.TreeNodeID, newParentID widget.TreeNodeID, index int) (widget.TreeNodeID, error) {
+	prevParentID, ok := r.parentMap[nodeID]
+	if !ok {
+		return "", ErrNoSuchNode
+	}
+	newParent, ok := r.idMap[newParentID]
+	if !ok {
+		return "", ErrNoSuchParent
+	}
+	for id := newParentID; id != ModelRoot; id = r.parentMap[id] {
+		if id == nodeID {
+			return "", ErrInvalidMove
+		}
+	}
+	node := r.idMap[nodeID]
+	prevParent := r.idMap[prevParentID]
+	prevSiblings := r.childMap[prevParentID]
+	prevIndex := indexOfID(prevSiblings, nodeID)
+	prevSiblings = removeID(prevSiblings, prevIndex)
+
+	siblings := r.childMap[newParentID]
+	if newParentID == prevParentID {
+		siblings = prevSiblings
+	} else if newParent != nil {
+		if err := CheckChildPolicy(newParent, node); err != nil {
+			return "", err
+		}
+	}
+	if index < 0 || index > len(siblings) {
+		index = len(siblings)
+	}
+
+	r.propagateRemove(prevParent, node)
+	if newParent != nil {
+		if err := newParent.AddChildAt(index, node); err != nil {
+			if prevParent != nil {
+				_ = prevParent.AddChildAt(prevIndex, node)
+			}
+			return "", err
+		}
+	}
+
+	r.childMap[prevParentID] = prevSiblings
+	if len(prevSiblings) == 0 {
+		delete(r.childMap, prevParentID)
+	}
+	r.childMap[newParentID] = insertID(siblings, index, nodeID)
+	r.parentMap[nodeID] = newParentID
+	return prevParentID, nil
+}
+
 func (r *TreeModelRegistry) Node(nodeID widget.TreeNodeID) TreeModel {
 	r.mux.RLock()
 	defer r.mux.RUnlock()
@@ -145,10 +284,152 @@ func (r *TreeModelRegistry) Children(parentID widget.TreeNodeID) []widget.TreeNo
 func (r *TreeModelRegistry) HasChildren(parentID widget.TreeNodeID) bool {
 	r.mux.RLock()
 	defer r.mux.RUnlock()
+	return r.hasChildren(parentID)
+}
+
+// hasChildren is HasChildren for callers holding the lock.
+func (r *TreeModelRegistry) hasChildren(parentID widget.TreeNodeID) bool {
+	if r.unloaded[parentID] {
+		return r.idMap[parentID].(LazyTreeModel).MayHaveChildren()
+	}
 	_, ok := r.childMap[parentID]
 	return ok
 }
 
+// VisibleIDs returns the IDs a tree would display in order, depth-first, given which branches are open.
+func (r *TreeModelRegistry) VisibleIDs(isOpen func(id widget.TreeNodeID) bool) []widget.TreeNodeID {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	var visible []widget.TreeNodeID
+	var visit func(parentID widget.TreeNodeID)
+	visit = func(parentID widget.TreeNodeID) {
+		for _, id := range r.childMap[parentID] {
+			visible = append(visible, id)
+			if isOpen(id) {
+				visit(id)
+			}
+		}
+	}
+	visit(ModelRoot)
+	return visible
+}
+
+// Ancestors returns the IDs of every ancestor of nodeID, starting with its root.
+// Nil is returned for roots and unregistered IDs.
+func (r *TreeModelRegistry) Ancestors(nodeID widget.TreeNodeID) []widget.TreeNodeID {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	var ancestors []widget.TreeNodeID
+	for id := r.parentMap[nodeID]; id != ModelRoot; id = r.parentMap[id] {
+		ancestors = append(ancestors, id)
+	}
+	for i, j := 0, len(ancestors)-1; i < j; i, j = i+1, j-1 {
+		ancestors[i], ancestors[j] = ancestors[j], ancestors[i]
+	}
+	return ancestors
+}
+
+// Branches returns the IDs of nodes with children in depth-first order, down to but not including maxDepth.
+// Roots have a depth of 0, and a negative maxDepth returns branches at any depth. Lazy nodes that haven't been loaded
+// are included if they may have children, but their descendants aren't known yet.
+func (r *TreeModelRegistry) Branches(maxDepth int) []widget.TreeNodeID {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	var branches []widget.TreeNodeID
+	var visit func(parentID widget.TreeNodeID, depth int)
+	visit = func(parentID widget.TreeNodeID, depth int) {
+		if maxDepth >= 0 && depth >= maxDepth {
+			return
+		}
+		for _, id := range r.childMap[parentID] {
+			if !r.hasChildren(id) {
+				continue
+			}
+			branches = append(branches, id)
+			visit(id, depth+1)
+		}
+	}
+	visit(ModelRoot, 0)
+	return branches
+}
+
+// Updated notifies listeners that the model registered as nodeID has changed and should be displayed again.
+// Nothing happens if nodeID isn't registered.
+func (r *TreeModelRegistry) Updated(nodeID widget.TreeNodeID) {
+	r.mux.RLock()
+	parentID, ok := r.parentMap[nodeID]
+	r.mux.RUnlock()
+	if !ok {
+		return
+	}
+	r.notify(RegistryEvent{Kind: NodeUpdated, ID: nodeID, ParentID: parentID})
+}
+
+// AddListener registers a listener to be notified of changes to the registry. The returned function removes it.
+func (r *TreeModelRegistry) AddListener(listener RegistryListener) (remove func()) {
+	entry := &registryListener{listener: listener}
+	r.listenerMux.Lock()
+	r.listeners = append(r.listeners, entry)
+	r.listenerMux.Unlock()
+	return func() {
+		r.listenerMux.Lock()
+		defer r.listenerMux.Unlock()
+		for i, l := range r.listeners {
+			if l == entry {
+				r.listeners = append(r.listeners[:i:i], r.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// lock locks the registry for writing. Models are called while it's locked, and may report that they've changed from
+// those calls, so their updates are held until unlock instead of waiting for the lock.
+func (r *TreeModelRegistry) lock() {
+	r.mux.Lock()
+	r.heldMux.Lock()
+	r.holding = true
+	r.heldMux.Unlock()
+}
+
+// unlock unlocks the registry, returning the nodes updated while it was locked so they can be sent with sendHeld once
+// the change itself has been sent.
+func (r *TreeModelRegistry) unlock() []widget.TreeNodeID {
+	r.heldMux.Lock()
+	held := r.held
+	r.holding, r.held = false, nil
+	r.heldMux.Unlock()
+	r.mux.Unlock()
+	return held
+}
+
+func (r *TreeModelRegistry) sendHeld(held []widget.TreeNodeID) {
+	for _, id := range held {
+		r.Updated(id)
+	}
+}
+
+// modelChanged is called when an ObservableTreeModel reports a change, which is held while the registry is locked.
+func (r *TreeModelRegistry) modelChanged(nodeID widget.TreeNodeID) {
+	r.heldMux.Lock()
+	if r.holding {
+		r.held = append(r.held, nodeID)
+		r.heldMux.Unlock()
+		return
+	}
+	r.heldMux.Unlock()
+	r.Updated(nodeID)
+}
+
+func (r *TreeModelRegistry) notify(event RegistryEvent) {
+	r.listenerMux.RLock()
+	listeners := r.listeners
+	r.listenerMux.RUnlock()
+	for _, l := range listeners {
+		l.listener(event)
+	}
+}
+
 type TreeModelWalkFunc = func(parentID widget.TreeNodeID, parent TreeModel, nodeID widget.TreeNodeID, node TreeModel)
 
 // Walk traverses the registered tree, depth-first. Attempting to modify the tree while walking will result in a deadlock.
@@ -170,10 +451,63 @@ func (r *TreeModelRegistry) walk(parentID, nodeID widget.TreeNodeID, walker Tree
 	}
 }
 
-func (r *TreeModelRegistry) getID() widget.TreeNodeID {
-	id, err := uuid.NewRandom()
-	for err != nil {
-		panic(fmt.Errorf("failed to generate UUID: %v", err))
+// getID creates a unique ID for child with the registry's IDStrategy. This must be called while the registry is locked.
+func (r *TreeModelRegistry) getID(parentID widget.TreeNodeID, child TreeModel) widget.TreeNodeID {
+	strategy := r.idStrategy
+	if strategy == nil {
+		strategy = UUIDStrategy
+	}
+	id := strategy(parentID, child)
+	if id == ModelRoot {
+		return UUIDStrategy(parentID, child)
+	}
+	if _, taken := r.idMap[id]; !taken {
+		return id
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s#%d", id, i)
+		if _, taken := r.idMap[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// isNilModel catches typed nil pointers that would otherwise pass a nil interface check.
+func isNilModel(model TreeModel) bool {
+	if model == nil {
+		return true
+	}
+	val := reflect.ValueOf(model)
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Interface, reflect.Chan:
+		return val.IsNil()
+	}
+	return false
+}
+
+func indexOfID(ids []widget.TreeNodeID, id widget.TreeNodeID) int {
+	for i, cid := range ids {
+		if cid == id {
+			return i
+		}
 	}
-	return id.String()
+	return -1
+}
+
+// removeID returns a new slice without the ID at index, so slices already returned by Children aren't changed.
+func removeID(ids []widget.TreeNodeID, index int) []widget.TreeNodeID {
+	if index < 0 {
+		return ids
+	}
+	removed := make([]widget.TreeNodeID, 0, len(ids)-1)
+	removed = append(removed, ids[:index]...)
+	return append(removed, ids[index+1:]...)
+}
+
+// insertID returns a new slice with id inserted at index.
+func insertID(ids []widget.TreeNodeID, index int, id widget.TreeNodeID) []widget.TreeNodeID {
+	inserted := make([]widget.TreeNodeID, 0, len(ids)+1)
+	inserted = append(inserted, ids[:index]...)
+	inserted = append(inserted, id)
+	return append(inserted, ids[index:]...)
 }
@@ -2,7 +2,10 @@ package generation
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
 	"testing"
+	"time"
 
 	"fyne.io/fyne/v2/widget"
 	testify "github.com/stretchr/testify/require"
@@ -123,6 +126,24 @@ func TestTreeModelRegistry_RemoveChild_DeregisterChildren(t *testing.T) {
 	assert.False(ok)
 }
 
+func TestTreeModelRegistry_RemoveChild_DeregisterManyChildren(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+
+	data := getTreeModelRegistryData()
+	for i := 0; i < 3; i++ {
+		assert.NoError(data.AddChild(getTreeModelRegistryData()))
+	}
+	dataID, err := reg.AddChild(ModelRoot, data)
+	assert.NoError(err)
+	assert.Len(reg.idMap, 5, "The root, data and its 3 children should be registered")
+
+	reg.RemoveChild(dataID)
+	assert.Len(reg.idMap, 1, "Only the root should be left")
+	assert.Len(reg.parentMap, 0)
+	assert.Len(reg.childMap, 0)
+}
+
 func TestTreeModelRegistry_RemoveNonexistentChild(t *testing.T) {
 	assert := testify.New(t)
 	reg := NewTreeModelRegistry()
@@ -156,6 +177,22 @@ func TestTreeModelRegistry_Children(t *testing.T) {
 	assert.Nil(dataChildren, "Returned child list should be nil")
 }
 
+func TestTreeModelRegistry_Children_NotChangedByRemove(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := reg.AddChild(ModelRoot, getTreeModelRegistryData())
+		assert.NoError(err)
+		ids = append(ids, id)
+	}
+	children := reg.Children(ModelRoot)
+	reg.RemoveChild(ids[0])
+	assert.Equal(ids, children, "Returned child lists shouldn't be changed by later removals")
+	assert.Equal(ids[1:], reg.Children(ModelRoot))
+}
+
 func TestTreeModelRegistry_HasChildren(t *testing.T) {
 	assert := testify.New(t)
 	reg := NewTreeModelRegistry()
@@ -220,6 +257,159 @@ func TestTreeModelRegistry_Walk(t *testing.T) {
 	assert.Equal(1, data2Visited, "Data2 should be visited as well")
 }
 
+func TestTreeModelRegistry_AddListener(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+
+	var events []RegistryEvent
+	remove := reg.AddListener(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	data := getTreeModelRegistryData()
+	dataID, err := reg.AddChild(ModelRoot, data)
+	assert.NoError(err)
+	reg.Updated(dataID)
+	reg.Updated("does not exist")
+	reg.RemoveChild(dataID)
+	reg.RemoveChild(dataID)
+
+	assert.Equal([]RegistryEvent{
+		{Kind: NodeAdded, ID: dataID, ParentID: ModelRoot},
+		{Kind: NodeUpdated, ID: dataID, ParentID: ModelRoot},
+		{Kind: NodeRemoved, ID: dataID, ParentID: ModelRoot},
+	}, events, "Only changes to registered nodes should be sent")
+
+	remove()
+	_, err = reg.AddChild(ModelRoot, getTreeModelRegistryData())
+	assert.NoError(err)
+	assert.Len(events, 3, "Removed listeners should not be notified")
+}
+
+func TestTreeModelRegistry_ObservableTreeModel(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+
+	var updated []widget.TreeNodeID
+	reg.AddListener(func(event RegistryEvent) {
+		if event.Kind == NodeUpdated {
+			updated = append(updated, event.ID)
+		}
+	})
+
+	data := &ModelData{Data: "A"}
+	child := &ModelData{Data: "B"}
+	assert.NoError(data.AddChild(child))
+	dataID, err := reg.AddChild(ModelRoot, data)
+	assert.NoError(err)
+	childID := reg.Children(dataID)[0]
+
+	child.NotifyChanged()
+	data.NotifyChanged()
+	assert.Equal([]widget.TreeNodeID{childID, dataID}, updated, "Models registered indirectly should be observed too")
+
+	reg.RemoveChild(dataID)
+	child.NotifyChanged()
+	assert.Len(updated, 2, "Removed models should no longer be observed")
+}
+
+func TestTreeModelRegistry_ObservableTreeModel_SharedModel(t *testing.T) {
+	assert := testify.New(t)
+	model := &ModelData{Data: "shared"}
+	updated := map[*TreeModelRegistry]int{}
+	var registries []*TreeModelRegistry
+	var ids []widget.TreeNodeID
+	for i := 0; i < 2; i++ {
+		reg := NewTreeModelRegistry()
+		reg.AddListener(func(event RegistryEvent) {
+			if event.Kind == NodeUpdated {
+				updated[reg]++
+			}
+		})
+		id, err := reg.AddChild(ModelRoot, model)
+		assert.NoError(err)
+		registries = append(registries, reg)
+		ids = append(ids, id)
+	}
+
+	model.NotifyChanged()
+	assert.Equal(1, updated[registries[0]])
+	assert.Equal(1, updated[registries[1]], "Every registry with the model should be notified")
+
+	registries[0].RemoveChild(ids[0])
+	model.NotifyChanged()
+	assert.Equal(1, updated[registries[0]])
+	assert.Equal(2, updated[registries[1]], "Removing the model from one registry shouldn't stop the other's updates")
+}
+
+// countingModel shows how many children it has, so it notifies the registry as they're added and removed.
+type countingModel struct {
+	ModelData
+}
+
+func (m *countingModel) DisplayString() string {
+	return fmt.Sprintf("%s (%d)", m.Data, len(m.Children()))
+}
+
+func (m *countingModel) AddChild(child TreeModel) error {
+	if err := m.ModelData.AddChild(child); err != nil {
+		return err
+	}
+	m.NotifyChanged()
+	return nil
+}
+
+func (m *countingModel) RemoveChildAt(index int) TreeModel {
+	removed := m.ModelData.RemoveChildAt(index)
+	m.NotifyChanged()
+	return removed
+}
+
+func TestTreeModelRegistry_ObservableTreeModel_NotifyWhileLocked(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	var events []RegistryEvent
+	reg.AddListener(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	done := make(chan struct{})
+	var parentID, childID widget.TreeNodeID
+	go func() {
+		defer close(done)
+		parentID, _ = reg.AddChild(ModelRoot, &countingModel{ModelData: ModelData{Data: "parent"}})
+		childID, _ = reg.AddChild(parentID, &ModelData{Data: "child"})
+		reg.RemoveChild(childID)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Models notifying the registry from AddChild or RemoveChildAt shouldn't deadlock it")
+	}
+	assert.Equal([]RegistryEvent{
+		{Kind: NodeAdded, ID: parentID, ParentID: ModelRoot},
+		{Kind: NodeAdded, ID: childID, ParentID: parentID},
+		{Kind: NodeUpdated, ID: parentID, ParentID: ModelRoot},
+		{Kind: NodeRemoved, ID: childID, ParentID: parentID},
+		{Kind: NodeUpdated, ID: parentID, ParentID: ModelRoot},
+	}, events, "Updates made while the registry is locked should be sent after the change")
+}
+
+func TestTreeModelRegistry_VisibleIDs(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+
+	assert.Equal([]widget.TreeNodeID{ids["root"]}, reg.VisibleIDs(func(widget.TreeNodeID) bool {
+		return false
+	}))
+	assert.Equal([]widget.TreeNodeID{ids["root"], ids["a"], ids["b"]}, reg.VisibleIDs(func(id widget.TreeNodeID) bool {
+		return id == ids["root"]
+	}))
+	assert.Equal([]widget.TreeNodeID{ids["root"], ids["a"], ids["a1"], ids["a2"], ids["b"]}, reg.VisibleIDs(func(widget.TreeNodeID) bool {
+		return true
+	}))
+}
+
 type ModelData struct {
 	BaseTreeModel
 	Data string
@@ -235,3 +425,201 @@ func getTreeModelRegistryData() TreeModel {
 	}
 	return &data
 }
+
+func TestTreeModelRegistry_MoveChild(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+
+	var events []RegistryEvent
+	reg.AddListener(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	oldChildren := reg.Children(ids["a"])
+	assert.NoError(reg.MoveChild(ids["a1"], ids["b"], -1))
+	assert.Equal(ids["b"], reg.Parent(ids["a1"]))
+	assert.Equal([]widget.TreeNodeID{ids["a2"]}, reg.Children(ids["a"]))
+	assert.Equal([]widget.TreeNodeID{ids["a1"], ids["a2"]}, oldChildren, "Previously returned child lists should not change")
+	assert.Equal([]RegistryEvent{{Kind: NodeMoved, ID: ids["a1"], ParentID: ids["b"], PreviousParentID: ids["a"]}}, events)
+
+	assert.NoError(reg.MoveChild(ids["a"], ModelRoot, 0))
+	assert.Equal([]widget.TreeNodeID{ids["a"], ids["root"]}, reg.Children(ModelRoot), "Nodes can be moved to the root")
+	assert.Len(reg.Node(ids["root"]).Children(), 1, "The old parent model should no longer have the node")
+
+	assert.True(errors.Is(reg.MoveChild(ids["root"], ids["a1"], 0), ErrInvalidMove), "A node can't be moved into its descendants")
+	assert.True(errors.Is(reg.MoveChild("nope", ids["a"], 0), ErrNoSuchNode))
+	assert.True(errors.Is(reg.MoveChild(ids["a1"], "nope", 0), ErrNoSuchParent))
+}
+
+func TestTreeModelRegistry_Ancestors(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+
+	assert.Equal([]widget.TreeNodeID{ids["root"], ids["a"]}, reg.Ancestors(ids["a1"]))
+	assert.Equal([]widget.TreeNodeID{ids["root"]}, reg.Ancestors(ids["b"]))
+	assert.Nil(reg.Ancestors(ids["root"]), "Roots have no ancestors")
+	assert.Nil(reg.Ancestors("nope"))
+}
+
+func TestTreeModelRegistry_Branches(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+
+	assert.Equal([]widget.TreeNodeID{ids["root"], ids["a"]}, reg.Branches(-1))
+	assert.Equal([]widget.TreeNodeID{ids["root"]}, reg.Branches(1), "Only roots have a depth less than 1")
+	assert.Nil(reg.Branches(0))
+
+	reg.RemoveChild(ids["a1"])
+	reg.RemoveChild(ids["a2"])
+	assert.Equal([]widget.TreeNodeID{ids["root"]}, reg.Branches(-1), "Nodes without children aren't branches")
+}
+
+// registryReference is the tree a registry should hold after a sequence of operations, kept without any of the
+// registry's bookkeeping.
+type registryReference struct {
+	children map[widget.TreeNodeID][]widget.TreeNodeID
+	parents  map[widget.TreeNodeID]widget.TreeNodeID
+	nodes    []widget.TreeNodeID // nodes are every ID but the root, in the order they were added.
+}
+
+func (ref *registryReference) add(parentID, id widget.TreeNodeID) {
+	ref.children[parentID] = append(ref.children[parentID], id)
+	ref.parents[id] = parentID
+	ref.nodes = append(ref.nodes, id)
+}
+
+func (ref *registryReference) remove(id widget.TreeNodeID) {
+	for _, child := range ref.children[id] {
+		ref.remove(child)
+	}
+	parentID := ref.parents[id]
+	ref.children[parentID] = withoutID(ref.children[parentID], id)
+	ref.nodes = withoutID(ref.nodes, id)
+	delete(ref.children, id)
+	delete(ref.parents, id)
+}
+
+func (ref *registryReference) move(id, parentID widget.TreeNodeID, index int) {
+	oldParentID := ref.parents[id]
+	ref.children[oldParentID] = withoutID(ref.children[oldParentID], id)
+	siblings := ref.children[parentID]
+	if index < 0 || index > len(siblings) {
+		index = len(siblings)
+	}
+	moved := append([]widget.TreeNodeID{}, siblings[:index]...)
+	moved = append(moved, id)
+	ref.children[parentID] = append(moved, siblings[index:]...)
+	ref.parents[id] = parentID
+}
+
+// isWithin reports whether id is ancestor or one of its descendants.
+func (ref *registryReference) isWithin(id, ancestor widget.TreeNodeID) bool {
+	for ; id != ModelRoot; id = ref.parents[id] {
+		if id == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutID(ids []widget.TreeNodeID, id widget.TreeNodeID) []widget.TreeNodeID {
+	var without []widget.TreeNodeID
+	for _, candidate := range ids {
+		if candidate != id {
+			without = append(without, candidate)
+		}
+	}
+	return without
+}
+
+// runRegistryOperations decodes ops into adds, removes and moves, four bytes at a time, and applies them to a registry
+// and a registryReference. The registry is validated and compared with the reference after every step.
+func runRegistryOperations(t *testing.T, ops []byte) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	ref := &registryReference{
+		children: map[widget.TreeNodeID][]widget.TreeNodeID{},
+		parents:  map[widget.TreeNodeID]widget.TreeNodeID{},
+	}
+	pick := func(b byte, includeRoot bool) widget.TreeNodeID {
+		if includeRoot {
+			if i := int(b) % (len(ref.nodes) + 1); i < len(ref.nodes) {
+				return ref.nodes[i]
+			}
+			return ModelRoot
+		}
+		return ref.nodes[int(b)%len(ref.nodes)]
+	}
+	added := 0
+	for i := 0; i+3 < len(ops); i += 4 {
+		op, x, y, z := ops[i]%4, ops[i+1], ops[i+2], ops[i+3]
+		switch {
+		case op < 2 || len(ref.nodes) == 0:
+			parentID := pick(x, true)
+			model := &ModelData{Data: fmt.Sprint(added)}
+			var preset []TreeModel
+			for j := 0; j < int(z)%3; j++ {
+				child := &ModelData{Data: fmt.Sprintf("%d.%d", added, j)}
+				assert.NoError(model.AddChild(child))
+				preset = append(preset, child)
+			}
+			added++
+			id, err := reg.AddChild(parentID, model)
+			assert.NoError(err)
+			ref.add(parentID, id)
+			childIDs := reg.Children(id)
+			assert.Len(childIDs, len(preset), "Children the model already has should be registered")
+			for j, childID := range childIDs {
+				assert.Same(preset[j], reg.Node(childID))
+				ref.add(id, childID)
+			}
+		case op == 2:
+			id := pick(x, false)
+			reg.RemoveChild(id)
+			ref.remove(id)
+		default:
+			id := pick(x, false)
+			parentID := pick(y, true)
+			index := int(z)%(len(ref.children[parentID])+2) - 1
+			err := reg.MoveChild(id, parentID, index)
+			if ref.isWithin(parentID, id) {
+				assert.ErrorIs(err, ErrInvalidMove)
+			} else {
+				assert.NoError(err)
+				ref.move(id, parentID, index)
+			}
+		}
+
+		assert.NoError(reg.Validate(), "After operation %d", i/4)
+		assert.Len(reg.idMap, len(ref.nodes)+1, "Only the reference's nodes and the root should be registered")
+		for _, id := range append([]widget.TreeNodeID{ModelRoot}, ref.nodes...) {
+			assert.Equal(len(ref.children[id]), len(reg.Children(id)))
+			if len(ref.children[id]) > 0 {
+				assert.Equal(ref.children[id], reg.Children(id), "Children of %q should match the reference", id)
+			}
+			assert.Equal(ref.parents[id], reg.Parent(id))
+		}
+	}
+}
+
+func FuzzTreeModelRegistry(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 0, 0, 1, 0, 2, 3, 1, 0, 0, 2, 0, 0, 0})
+	f.Add([]byte{1, 0, 0, 2, 0, 1, 0, 0, 3, 0, 1, 1, 3, 2, 0, 3, 2, 1, 0, 0})
+	f.Add([]byte{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1, 3, 0, 3, 0, 3, 5, 0, 255, 2, 3, 0, 0})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		// Every step is validated against the whole tree, so long inputs are cut short to keep each run quick.
+		if len(ops) > 400 {
+			ops = ops[:400]
+		}
+		runRegistryOperations(t, ops)
+	})
+}
+
+func TestTreeModelRegistry_RandomOperations(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		ops := make([]byte, 4*random.Intn(60))
+		random.Read(ops)
+		runRegistryOperations(t, ops)
+	}
+}
@@ -0,0 +1,42 @@
+package generation
+
+import (
+	"fyne.io/fyne/v2/widget"
+	"github.com/pkg/errors"
+)
+
+// ErrNotRenamable is returned when renaming a node whose model doesn't implement RenamableTreeModel.
+var ErrNotRenamable = errors.New("node can't be renamed")
+
+// RenamableTreeModel may be implemented by a TreeModel to allow its display string to be edited. Models that also
+// implement ObservableTreeModel should notify their listeners from SetDisplayString.
+type RenamableTreeModel interface {
+	SetDisplayString(name string) error // SetDisplayString changes the model's display string, or returns an error explaining why name isn't valid.
+}
+
+// CanRename returns whether the node identified by id can be renamed.
+func (r *TreeModelRegistry) CanRename(id widget.TreeNodeID) bool {
+	_, ok := r.Node(id).(RenamableTreeModel)
+	return ok
+}
+
+// Rename sets the display string of the node identified by id and notifies listeners that it was updated, unless the
+// model is an ObservableTreeModel, which notifies them itself. Errors from the model are returned as-is, so they can be
+// shown to the user.
+func (r *TreeModelRegistry) Rename(id widget.TreeNodeID, name string) error {
+	model := r.Node(id)
+	if model == nil {
+		return ErrNoSuchNode
+	}
+	renamable, ok := model.(RenamableTreeModel)
+	if !ok {
+		return ErrNotRenamable
+	}
+	if err := renamable.SetDisplayString(name); err != nil {
+		return err
+	}
+	if _, ok := model.(ObservableTreeModel); !ok {
+		r.Updated(id)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	testify "github.com/stretchr/testify/require"
+)
+
+// unobservedModel renames a model that doesn't notify its own changes.
+type unobservedModel struct {
+	TreeModel
+	name string
+}
+
+func (m *unobservedModel) DisplayString() string {
+	return m.name
+}
+
+func (m *unobservedModel) SetDisplayString(name string) error {
+	m.name = name
+	return nil
+}
+
+func TestTreeModelRegistry_Rename(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	renamable := &renamableModel{ModelData{Data: "before"}}
+	id, err := reg.AddChild(ModelRoot, renamable)
+	assert.NoError(err)
+	fixedID, err := reg.AddChild(ModelRoot, &ModelData{Data: "fixed"})
+	assert.NoError(err)
+
+	var events []RegistryEvent
+	reg.AddListener(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	assert.True(reg.CanRename(id))
+	assert.False(reg.CanRename(fixedID))
+	assert.NoError(reg.Rename(id, "after"))
+	assert.Equal("after", renamable.DisplayString())
+	assert.Equal([]RegistryEvent{{Kind: NodeUpdated, ID: id}}, events, "Renaming should notify listeners once")
+
+	assert.EqualError(reg.Rename(id, ""), "name is required", "Model errors should be returned as-is")
+	assert.Equal("after", renamable.DisplayString())
+	assert.Len(events, 1)
+
+	assert.True(errors.Is(reg.Rename(fixedID, "nope"), ErrNotRenamable))
+	assert.True(errors.Is(reg.Rename("nope", "nope"), ErrNoSuchNode))
+}
+
+func TestTreeModelRegistry_RenameUnobserved(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	id, err := reg.AddChild(ModelRoot, &unobservedModel{TreeModel: &ModelData{}, name: "before"})
+	assert.NoError(err)
+	var events []RegistryEvent
+	reg.AddListener(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	assert.NoError(reg.Rename(id, "after"))
+	assert.Equal([]RegistryEvent{{Kind: NodeUpdated, ID: id}}, events, "The registry should notify listeners for models that can't")
+}
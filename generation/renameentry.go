@@ -0,0 +1,112 @@
+package generation
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var _ fyne.Widget = (*RenameEntry)(nil)
+
+// RenameEntry is a widget.Entry for editing text in place. Pressing Enter or moving focus away commits the edit, and
+// pressing Escape cancels it.
+type RenameEntry struct {
+	widget.Entry
+	OnCommit func(text string) error // OnCommit is called with the edited text. Returning an error keeps the entry editing.
+	OnCancel func()                  // OnCancel is called when an edit is cancelled with Escape.
+
+	mux     sync.RWMutex
+	editing bool
+}
+
+// NewRenameEntry creates a RenameEntry that isn't editing yet.
+func NewRenameEntry() *RenameEntry {
+	e := &RenameEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// Edit starts editing text, with all of it selected. The entry is focused if it's already on a canvas.
+func (e *RenameEntry) Edit(text string) {
+	e.mux.Lock()
+	e.editing = true
+	e.mux.Unlock()
+	e.SetText(text)
+	if c := fyne.CurrentApp().Driver().CanvasForObject(e); c != nil {
+		c.Focus(e)
+	}
+	e.TypedShortcut(&fyne.ShortcutSelectAll{})
+}
+
+// Editing returns whether an edit is in progress.
+func (e *RenameEntry) Editing() bool {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	return e.editing
+}
+
+// Cancel ends the edit without committing it or calling OnCancel.
+func (e *RenameEntry) Cancel() {
+	e.stop(true)
+}
+
+// TypedKey commits the edit on Enter and cancels it on Escape, and otherwise behaves like widget.Entry.
+func (e *RenameEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyReturn, fyne.KeyEnter:
+		// widget.Entry erases selected text before submitting, which would commit an empty name straight after Edit.
+		e.commit(true)
+	case fyne.KeyEscape:
+		if e.stop(true) && e.OnCancel != nil {
+			e.OnCancel()
+		}
+	default:
+		e.Entry.TypedKey(key)
+	}
+}
+
+// FocusLost commits the edit, like pressing Enter.
+func (e *RenameEntry) FocusLost() {
+	e.Entry.FocusLost()
+	// The canvas is still changing focus, so it can't be asked to unfocus.
+	e.commit(false)
+}
+
+// MinSize is wide enough to show all of the text, since the surrounding layout may not stretch the entry.
+func (e *RenameEntry) MinSize() fyne.Size {
+	min := e.Entry.MinSize()
+	textSize := fyne.MeasureText(e.Text+"MM", theme.TextSize(), e.TextStyle)
+	if width := textSize.Width + theme.Padding()*4; width > min.Width {
+		min.Width = width
+	}
+	return min
+}
+
+func (e *RenameEntry) commit(unfocus bool) {
+	if !e.Editing() {
+		return
+	}
+	if e.OnCommit != nil {
+		if err := e.OnCommit(e.Text); err != nil {
+			return
+		}
+	}
+	e.stop(unfocus)
+}
+
+// stop ends editing and optionally gives up focus, returning false if there was no edit in progress.
+func (e *RenameEntry) stop(unfocus bool) bool {
+	e.mux.Lock()
+	editing := e.editing
+	e.editing = false
+	e.mux.Unlock()
+	if !editing || !unfocus {
+		return editing
+	}
+	if c := fyne.CurrentApp().Driver().CanvasForObject(e); c != nil && c.Focused() == e {
+		c.Unfocus()
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestRenameEntry_Commit(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	var committed []string
+	commitErr := errors.New("invalid")
+	entry := NewRenameEntry()
+	entry.OnCommit = func(text string) error {
+		committed = append(committed, text)
+		if text == "bad" {
+			return commitErr
+		}
+		return nil
+	}
+	w := test.NewWindow(entry)
+	defer w.Close()
+
+	entry.Edit("bad")
+	assert.True(entry.Editing())
+	assert.Equal("bad", entry.SelectedText(), "Editing should select the text")
+	assert.Equal(entry, w.Canvas().Focused(), "Editing should focus the entry")
+	entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.True(entry.Editing(), "A failed commit should keep editing")
+
+	entry.SetText("good")
+	entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.False(entry.Editing())
+	assert.Nil(w.Canvas().Focused(), "Committing should give up focus")
+
+	entry.Edit("lost focus")
+	w.Canvas().Unfocus()
+	assert.False(entry.Editing(), "Losing focus should commit")
+	assert.Equal([]string{"bad", "good", "lost focus"}, committed)
+}
+
+func TestRenameEntry_Cancel(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	var cancelled int
+	entry := NewRenameEntry()
+	entry.OnCommit = func(string) error {
+		t.Fatal("Cancelling should not commit")
+		return nil
+	}
+	entry.OnCancel = func() {
+		cancelled++
+	}
+	w := test.NewWindow(entry)
+	defer w.Close()
+
+	entry.Edit("text")
+	entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyEscape})
+	assert.False(entry.Editing())
+	assert.Equal(1, cancelled)
+
+	entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyEscape})
+	assert.Equal(1, cancelled, "Escape should do nothing when not editing")
+}
+
+func TestRenameEntry_AbandonedEdit(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	entry := NewRenameEntry()
+	entry.OnCommit = func(string) error {
+		t.Fatal("Cancel should not commit")
+		return nil
+	}
+	entry.OnCancel = func() {
+		t.Fatal("Cancel should not call OnCancel")
+	}
+	w := test.NewWindow(entry)
+	defer w.Close()
+
+	entry.Edit("text")
+	entry.Cancel()
+	assert.False(entry.Editing())
+	assert.Nil(w.Canvas().Focused())
+}
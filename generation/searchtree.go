@@ -0,0 +1,185 @@
+package generation
+
+import (
+	"sync"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+var _ fyne.Widget = (*SearchTree)(nil)
+
+// SearchTree shows an entry above a Tree, and filters the tree as text is typed to the nodes whose display string
+// contains it, ignoring case, along with their ancestors. Matches are revealed and the matched text is highlighted.
+// Clearing the entry shows every node again with the branches that were open before searching.
+type SearchTree struct {
+	widget.BaseWidget
+	Entry *widget.Entry // Entry is where the search is typed.
+	Tree  *Tree         // Tree displays the nodes, and may be used like any other Tree.
+
+	mux      sync.RWMutex
+	unlisten func() // unlisten removes the registry listener.
+	query    string
+	saved    map[widget.TreeNodeID]bool // saved holds the branches that were open before searching, and is nil while not searching.
+	matches  map[widget.TreeNodeID]bool // matches holds the nodes that matched the query when the tree was last filtered.
+}
+
+// NewSearchTree creates a SearchTree whose Tree is created with options. Matches are highlighted in each node's label,
+// keeping the rest of its presentation. Content given with WithNodeContent is shown as it is, and may call Query to
+// highlight matches itself.
+func NewSearchTree(options ...TreeOption) *SearchTree {
+	s := &SearchTree{
+		Entry: widget.NewEntry(),
+	}
+	s.Entry.SetPlaceHolder("Search")
+	s.Entry.OnChanged = s.Search
+	s.Tree = NewTree(options...)
+	s.Tree.highlight = func(text string) [][2]int {
+		return matchRanges(text, s.Query())
+	}
+	s.unlisten = s.Tree.AddListener(s.registryChanged)
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// Close stops the SearchTree and its Tree following changes to the registry, so they can be released while a shared
+// registry outlives them.
+func (s *SearchTree) Close() {
+	s.unlisten()
+	s.Tree.Close()
+}
+
+// Query returns the text being searched for, which is empty while not searching.
+func (s *SearchTree) Query() string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.query
+}
+
+// Search filters the tree to nodes matching query and their ancestors, and opens every ancestor so the matches are
+// displayed. An empty query ends the search, restoring the branches that were open before it started. It's called as
+// the entry changes, but doesn't change the entry's text.
+func (s *SearchTree) Search(query string) {
+	s.mux.Lock()
+	s.query = query
+	if query == "" {
+		saved := s.saved
+		s.saved, s.matches = nil, nil
+		s.mux.Unlock()
+		s.Tree.SetFilter(nil)
+		if saved != nil {
+			s.Tree.setOpen(func(id widget.TreeNodeID) bool {
+				return saved[id]
+			})
+		}
+		return
+	}
+	if s.saved == nil {
+		s.saved = map[widget.TreeNodeID]bool{}
+		for _, id := range s.Tree.Branches(-1) {
+			if s.Tree.IsBranchOpen(id) {
+				s.saved[id] = true
+			}
+		}
+	}
+	s.mux.Unlock()
+	s.filter(query, true)
+}
+
+// filter shows the nodes matching query and opens the ancestors of matches. With reset, every other branch is closed,
+// as when a search starts. Otherwise only the ancestors of new matches are opened, so branches opened or closed during
+// the search stay that way. The registry may be read while it's being walked, but the tree is only filtered once the
+// walk is done, since nothing can write to the registry until then.
+func (s *SearchTree) filter(query string, reset bool) {
+	keep := map[widget.TreeNodeID]bool{}
+	matches := map[widget.TreeNodeID][]widget.TreeNodeID{}
+	s.Tree.Walk(func(_ widget.TreeNodeID, _ TreeModel, nodeID widget.TreeNodeID, node TreeModel) {
+		if len(matchRanges(node.DisplayString(), query)) == 0 {
+			return
+		}
+		ancestors := s.Tree.Ancestors(nodeID)
+		matches[nodeID] = ancestors
+		keep[nodeID] = true
+		for _, ancestor := range ancestors {
+			keep[ancestor] = true
+		}
+	})
+	s.mux.Lock()
+	previous := s.matches
+	s.matches = map[widget.TreeNodeID]bool{}
+	for id := range matches {
+		s.matches[id] = true
+	}
+	s.mux.Unlock()
+	open := map[widget.TreeNodeID]bool{}
+	for id, ancestors := range matches {
+		if reset || !previous[id] {
+			for _, ancestor := range ancestors {
+				open[ancestor] = true
+			}
+		}
+	}
+	s.Tree.SetFilter(func(id widget.TreeNodeID) bool {
+		return keep[id]
+	})
+	s.Tree.setOpen(func(id widget.TreeNodeID) bool {
+		return open[id] || (!reset && s.Tree.IsBranchOpen(id))
+	})
+}
+
+// registryChanged searches again when the change could add or remove matches, keeping the branches that are open.
+// Updated nodes are only searched again if they've started or stopped matching.
+func (s *SearchTree) registryChanged(event RegistryEvent) {
+	query := s.Query()
+	if query == "" {
+		return
+	}
+	if event.Kind == NodeUpdated {
+		model := s.Tree.Node(event.ID)
+		matches := model != nil && len(matchRanges(model.DisplayString(), query)) > 0
+		s.mux.RLock()
+		matched := s.matches[event.ID]
+		s.mux.RUnlock()
+		if matches == matched {
+			return
+		}
+	}
+	s.filter(query, false)
+}
+
+func (s *SearchTree) CreateRenderer() fyne.WidgetRenderer {
+	s.ExtendBaseWidget(s)
+	return widget.NewSimpleRenderer(container.NewBorder(s.Entry, nil, nil, nil, s.Tree))
+}
+
+// matchRanges returns the start and end rune index of every place query appears in text, ignoring case. Matches don't
+// overlap, and nil is returned for an empty query.
+func matchRanges(text, query string) [][2]int {
+	needle := []rune(query)
+	if len(needle) == 0 {
+		return nil
+	}
+	for i, r := range needle {
+		needle[i] = unicode.ToLower(r)
+	}
+	haystack := []rune(text)
+	var ranges [][2]int
+	for i := 0; i+len(needle) <= len(haystack); {
+		matched := true
+		for j, r := range needle {
+			if unicode.ToLower(haystack[i+j]) != r {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			ranges = append(ranges, [2]int{i, i + len(needle)})
+			i += len(needle)
+		} else {
+			i++
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,169 @@
+package generation
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+// buildSearchTree creates a search tree with the models from buildModels, and shows it in a window. Only the root is
+// open.
+func buildSearchTree(t *testing.T) (*SearchTree, fyne.Window) {
+	test.NewApp()
+	search := NewSearchTree(WithIDStrategy(PathStrategy), WithRoots(buildModels(t)))
+	search.Tree.OpenBranch("root")
+	w := test.NewWindow(search)
+	w.Resize(fyne.NewSize(300, 400))
+	return search, w
+}
+
+// searchText returns the pieces of a node's label, which is split where it's highlighted.
+func searchText(t *testing.T, search *SearchTree, id widget.TreeNodeID) []string {
+	render := displayedNode(t, search.Tree, id).render
+	if !render.highlighted.Visible() {
+		return []string{render.label.Text}
+	}
+	var text []string
+	for _, piece := range render.highlighted.Objects {
+		text = append(text, piece.(*canvas.Text).Text)
+	}
+	return text
+}
+
+func TestMatchRanges(t *testing.T) {
+	assert := testify.New(t)
+	assert.Nil(matchRanges("anything", ""))
+	assert.Nil(matchRanges("abc", "x"))
+	assert.Equal([][2]int{{0, 3}}, matchRanges("Abc", "aBC"))
+	assert.Equal([][2]int{{0, 2}, {2, 4}}, matchRanges("aaaaa", "aa"), "Matches shouldn't overlap")
+	assert.Equal([][2]int{{1, 3}}, matchRanges("xÉtéx", "ét"), "Ranges should count runes")
+}
+
+func TestHighlightedText(t *testing.T) {
+	assert := testify.New(t)
+	p := NodePresentation{Text: "Report report", TextStyle: fyne.TextStyle{Italic: true}, TextColor: color.White}
+	pieces := highlightedText(p, matchRanges(p.Text, "port"))
+	assert.Len(pieces, 4)
+	var text []string
+	for _, piece := range pieces {
+		text = append(text, piece.(*canvas.Text).Text)
+	}
+	assert.Equal([]string{"Re", "port", " re", "port"}, text)
+	assert.Equal(color.White, pieces[0].(*canvas.Text).Color, "Text outside the matches should keep the model's color")
+	assert.Equal(fyne.TextStyle{Italic: true}, pieces[0].(*canvas.Text).TextStyle)
+	assert.Equal(theme.PrimaryColor(), pieces[1].(*canvas.Text).Color)
+	assert.Equal(fyne.TextStyle{Italic: true, Bold: true}, pieces[1].(*canvas.Text).TextStyle)
+}
+
+func TestSearchTree_Search(t *testing.T) {
+	assert := testify.New(t)
+	search, _ := buildSearchTree(t)
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/b"}, search.Tree.visibleIDs())
+
+	search.Entry.SetText("A2")
+	assert.Equal("A2", search.Query())
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/a2"}, search.Tree.visibleIDs(), "Matches should be revealed with their ancestors")
+	assert.Equal([]string{"a2"}, searchText(t, search, "root/a/a2"))
+	assert.Equal([]string{"a"}, searchText(t, search, "root/a"))
+
+	search.Entry.SetText("a")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/a1", "root/a/a2"}, search.Tree.visibleIDs())
+	assert.Equal([]string{"a", "1"}, searchText(t, search, "root/a/a1"), "The match should be split from the rest of the text")
+
+	search.Entry.SetText("nothing")
+	assert.Empty(search.Tree.visibleIDs())
+
+	search.Entry.SetText("")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/b"}, search.Tree.visibleIDs(), "Clearing the search should restore the open branches")
+	assert.False(search.Tree.IsBranchOpen("root/a"))
+	assert.Equal([]string{"a"}, searchText(t, search, "root/a"))
+}
+
+func TestSearchTree_RegistryChanges(t *testing.T) {
+	assert := testify.New(t)
+	search, _ := buildSearchTree(t)
+	search.Search("new")
+	assert.Empty(search.Tree.visibleIDs())
+
+	id, err := search.Tree.AddChild("root/b", &ModelData{Data: "new"})
+	assert.NoError(err)
+	assert.Equal([]widget.TreeNodeID{"root", "root/b", id}, search.Tree.visibleIDs(), "Added nodes should be searched too")
+	assert.Equal("", search.Entry.Text, "Searching from code shouldn't change the entry")
+}
+
+func TestSearchTree_KeepsOpenBranches(t *testing.T) {
+	assert := testify.New(t)
+	search, _ := buildSearchTree(t)
+	_, err := search.Tree.AddChild("root/b", &ModelData{Data: "b1"})
+	assert.NoError(err)
+	search.Search("1")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/a1", "root/b", "root/b/b1"}, search.Tree.visibleIDs())
+
+	search.Tree.CloseBranch("root/b")
+	a2 := search.Tree.Node("root/a/a2").(*ModelData)
+	a2.Data = "a2 renamed"
+	a2.NotifyChanged()
+	assert.False(search.Tree.IsBranchOpen("root/b"), "Updates that don't change the matches shouldn't reopen branches")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/a1", "root/b"}, search.Tree.visibleIDs())
+
+	a2.Data = "a21"
+	a2.NotifyChanged()
+	assert.False(search.Tree.IsBranchOpen("root/b"), "New matches shouldn't reopen other branches")
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/a1", "root/a/a2", "root/b"}, search.Tree.visibleIDs(), "Nodes that start matching should be shown")
+
+	a2.Data = "a2"
+	a2.NotifyChanged()
+	assert.Equal([]widget.TreeNodeID{"root", "root/a", "root/a/a1", "root/b"}, search.Tree.visibleIDs(), "Nodes that stop matching should be hidden")
+}
+
+func TestSearchTree_Close(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+	registry := NewTreeModelRegistry()
+	listeners := len(registry.listeners)
+	search := NewSearchTree(WithRegistry(registry))
+	search.Close()
+	assert.Len(registry.listeners, listeners, "A closed SearchTree shouldn't be kept by a shared registry")
+}
+
+func TestSearchTree_Presentation(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+	search := NewSearchTree(WithIDStrategy(PathStrategy), WithRoots(&presentedModel{ModelData: ModelData{Data: "rich"}}))
+	w := test.NewWindow(search)
+	defer w.Close()
+	w.Resize(fyne.NewSize(300, 400))
+
+	search.Search("ic")
+	render := displayedNode(t, search.Tree, "rich").render
+	assert.Equal([]string{"r", "ic", "h"}, searchText(t, search, "rich"))
+	assert.Equal(color.NRGBA{R: 0xff, A: 0xff}, render.highlighted.Objects[0].(*canvas.Text).Color, "The model's color should be kept outside the match")
+	assert.True(render.subtitle.Visible(), "The subtitle should still be shown while searching")
+	assert.True(render.badge.Visible(), "The badge should still be shown while searching")
+	assert.Equal(theme.FolderIcon(), render.icon.Resource)
+	assert.False(render.text.Visible())
+
+	search.Search("")
+	assert.False(render.highlighted.Visible())
+	assert.True(render.text.Visible(), "The colored text should be shown again once the search ends")
+}
+
+func TestSearchTree_NodeContent(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+	search := NewSearchTree(WithIDStrategy(PathStrategy), WithNodeContent(newProgressContent), WithRoots(&ModelData{Data: "root"}))
+	w := test.NewWindow(search)
+	defer w.Close()
+	w.Resize(fyne.NewSize(300, 400))
+
+	search.Search("roo")
+	content, ok := displayedNode(t, search.Tree, "root").render.custom.(*progressContent)
+	assert.True(ok, "The caller's node content should be kept")
+	assert.Equal("root: root", content.label.Text)
+}
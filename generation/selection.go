@@ -0,0 +1,198 @@
+package generation
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// SelectionSet tracks the selected nodes of a TreeModelRegistry, for trees that allow more than one node to be selected.
+// Nodes are dropped from the selection when they're removed from the registry.
+type SelectionSet struct {
+	OnChanged func(changed []widget.TreeNodeID) // OnChanged is called with every node that was selected or unselected, after the change is complete.
+
+	mux      sync.RWMutex
+	registry *TreeModelRegistry
+	unlisten func() // unlisten removes the registry listener.
+	selected map[widget.TreeNodeID]bool
+	anchor   widget.TreeNodeID
+}
+
+// NewSelectionSet creates a SelectionSet that follows changes to registry.
+func NewSelectionSet(registry *TreeModelRegistry) *SelectionSet {
+	sel := &SelectionSet{
+		registry: registry,
+		selected: map[widget.TreeNodeID]bool{},
+	}
+	sel.unlisten = registry.AddListener(sel.registryChanged)
+	return sel
+}
+
+// Close stops following changes to the registry, so a SelectionSet that's no longer used doesn't outlive it.
+func (s *SelectionSet) Close() {
+	s.unlisten()
+}
+
+// IsSelected returns whether id is selected.
+func (s *SelectionSet) IsSelected(id widget.TreeNodeID) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.selected[id]
+}
+
+// SelectedIDs returns the selected IDs in depth-first order.
+func (s *SelectionSet) SelectedIDs() []widget.TreeNodeID {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	var selected []widget.TreeNodeID
+	s.registry.Walk(func(_ widget.TreeNodeID, _ TreeModel, nodeID widget.TreeNodeID, _ TreeModel) {
+		if s.selected[nodeID] {
+			selected = append(selected, nodeID)
+		}
+	})
+	return selected
+}
+
+// Select replaces the selection with id, which also becomes the anchor for SelectRange.
+func (s *SelectionSet) Select(id widget.TreeNodeID) {
+	if !s.registered(id) {
+		return
+	}
+	s.update(func() {
+		s.anchor = id
+		s.replace(id)
+	})
+}
+
+// Toggle adds id to the selection, or removes it if it's already selected. The anchor is moved to id.
+func (s *SelectionSet) Toggle(id widget.TreeNodeID) {
+	if !s.registered(id) {
+		return
+	}
+	s.update(func() {
+		s.anchor = id
+		if s.selected[id] {
+			delete(s.selected, id)
+		} else {
+			s.selected[id] = true
+		}
+	})
+}
+
+// SelectIDs replaces the selection with every registered ID in ids. The last of them becomes the anchor.
+func (s *SelectionSet) SelectIDs(ids ...widget.TreeNodeID) {
+	var registered []widget.TreeNodeID
+	for _, id := range ids {
+		if s.registered(id) {
+			registered = append(registered, id)
+		}
+	}
+	s.update(func() {
+		s.anchor = ModelRoot
+		if len(registered) > 0 {
+			s.anchor = registered[len(registered)-1]
+		}
+		s.replace(registered...)
+	})
+}
+
+// SelectRange replaces the selection with every ID in order between the anchor and id, inclusive. order is usually the
+// IDs currently displayed, from TreeModelRegistry.VisibleIDs. If there is no anchor in order, this is the same as Select.
+func (s *SelectionSet) SelectRange(id widget.TreeNodeID, order []widget.TreeNodeID) {
+	s.mux.RLock()
+	anchor := s.anchor
+	s.mux.RUnlock()
+	start, end := -1, -1
+	for i, oid := range order {
+		if oid == anchor {
+			start = i
+		}
+		if oid == id {
+			end = i
+		}
+	}
+	if start < 0 || end < 0 {
+		s.Select(id)
+		return
+	}
+	if start > end {
+		start, end = end, start
+	}
+	s.update(func() {
+		s.replace(order[start : end+1]...)
+	})
+}
+
+// SelectAll selects every registered node.
+func (s *SelectionSet) SelectAll() {
+	var all []widget.TreeNodeID
+	s.registry.Walk(func(_ widget.TreeNodeID, _ TreeModel, nodeID widget.TreeNodeID, _ TreeModel) {
+		all = append(all, nodeID)
+	})
+	s.update(func() {
+		s.replace(all...)
+	})
+}
+
+// Clear unselects everything.
+func (s *SelectionSet) Clear() {
+	s.update(func() {
+		s.anchor = ModelRoot
+		s.replace()
+	})
+}
+
+func (s *SelectionSet) registered(id widget.TreeNodeID) bool {
+	return id != ModelRoot && s.registry.Node(id) != nil
+}
+
+// replace must be called within update.
+func (s *SelectionSet) replace(ids ...widget.TreeNodeID) {
+	s.selected = make(map[widget.TreeNodeID]bool, len(ids))
+	for _, id := range ids {
+		s.selected[id] = true
+	}
+}
+
+// update applies change while locked, and reports the difference to OnChanged.
+func (s *SelectionSet) update(change func()) {
+	s.mux.Lock()
+	before := s.selected
+	s.selected = make(map[widget.TreeNodeID]bool, len(before))
+	for id := range before {
+		s.selected[id] = true
+	}
+	change()
+	var changed []widget.TreeNodeID
+	for id := range before {
+		if !s.selected[id] {
+			changed = append(changed, id)
+		}
+	}
+	for id := range s.selected {
+		if !before[id] {
+			changed = append(changed, id)
+		}
+	}
+	s.mux.Unlock()
+	if len(changed) > 0 && s.OnChanged != nil {
+		s.OnChanged(changed)
+	}
+}
+
+func (s *SelectionSet) registryChanged(event RegistryEvent) {
+	if event.Kind != NodeRemoved {
+		return
+	}
+	s.update(func() {
+		// Descendants are already gone from the registry, so anything no longer registered is dropped.
+		for id := range s.selected {
+			if s.registry.Node(id) == nil {
+				delete(s.selected, id)
+			}
+		}
+		if s.anchor != ModelRoot && s.registry.Node(s.anchor) == nil {
+			s.anchor = ModelRoot
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestSelectionSet_Select(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	sel := NewSelectionSet(reg)
+
+	var reported []widget.TreeNodeID
+	sel.OnChanged = func(changed []widget.TreeNodeID) {
+		reported = changed
+	}
+
+	sel.Select(ids["a"])
+	assert.Equal([]widget.TreeNodeID{ids["a"]}, sel.SelectedIDs())
+	assert.Equal([]widget.TreeNodeID{ids["a"]}, reported)
+
+	sel.Select(ids["b"])
+	assert.True(sel.IsSelected(ids["b"]))
+	assert.False(sel.IsSelected(ids["a"]))
+	assert.ElementsMatch([]widget.TreeNodeID{ids["a"], ids["b"]}, reported, "Both the old and new selection changed")
+
+	reported = nil
+	sel.Select(ids["b"])
+	assert.Nil(reported, "Nothing should be reported without a change")
+
+	sel.Select("does not exist")
+	assert.Equal([]widget.TreeNodeID{ids["b"]}, sel.SelectedIDs())
+}
+
+func TestSelectionSet_Toggle(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	sel := NewSelectionSet(reg)
+
+	sel.Select(ids["a"])
+	sel.Toggle(ids["b"])
+	sel.Toggle(ids["a1"])
+	assert.Equal([]widget.TreeNodeID{ids["a"], ids["a1"], ids["b"]}, sel.SelectedIDs(), "Selection should be in depth-first order")
+	sel.Toggle(ids["a"])
+	assert.Equal([]widget.TreeNodeID{ids["a1"], ids["b"]}, sel.SelectedIDs())
+}
+
+func TestSelectionSet_SelectIDs(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	sel := NewSelectionSet(reg)
+
+	var reported []widget.TreeNodeID
+	sel.OnChanged = func(changed []widget.TreeNodeID) {
+		reported = changed
+	}
+
+	sel.Select(ids["a"])
+	sel.SelectIDs(ids["b"], "does not exist", ids["a1"])
+	assert.Equal([]widget.TreeNodeID{ids["a1"], ids["b"]}, sel.SelectedIDs(), "Unregistered IDs should be ignored")
+	assert.ElementsMatch([]widget.TreeNodeID{ids["a"], ids["a1"], ids["b"]}, reported)
+
+	sel.SelectIDs()
+	assert.Empty(sel.SelectedIDs())
+}
+
+func TestSelectionSet_SelectRange(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	sel := NewSelectionSet(reg)
+	order := []widget.TreeNodeID{ids["root"], ids["a"], ids["a1"], ids["a2"], ids["b"]}
+
+	sel.SelectRange(ids["a1"], order)
+	assert.Equal([]widget.TreeNodeID{ids["a1"]}, sel.SelectedIDs(), "Without an anchor only the node is selected")
+
+	sel.SelectRange(ids["b"], order)
+	assert.Equal([]widget.TreeNodeID{ids["a1"], ids["a2"], ids["b"]}, sel.SelectedIDs())
+
+	sel.SelectRange(ids["root"], order)
+	assert.Equal([]widget.TreeNodeID{ids["root"], ids["a"], ids["a1"]}, sel.SelectedIDs(), "Ranges can extend backward from the anchor")
+}
+
+func TestSelectionSet_SelectAllClear(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	sel := NewSelectionSet(reg)
+
+	sel.SelectAll()
+	assert.Len(sel.SelectedIDs(), len(ids))
+	sel.Clear()
+	assert.Empty(sel.SelectedIDs())
+}
+
+func TestSelectionSet_RegistryChanges(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	sel := NewSelectionSet(reg)
+
+	sel.Select(ids["a1"])
+	sel.Toggle(ids["b"])
+
+	var reported []widget.TreeNodeID
+	sel.OnChanged = func(changed []widget.TreeNodeID) {
+		reported = changed
+	}
+	reg.RemoveChild(ids["a"])
+	assert.Equal([]widget.TreeNodeID{ids["b"]}, sel.SelectedIDs())
+	assert.Equal([]widget.TreeNodeID{ids["a1"]}, reported, "Removed descendants should be unselected")
+	assert.Len(sel.selected, 1)
+}
+
+func TestSelectionSet_Close(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	sel := NewSelectionSet(reg)
+	sel.Select(ids["a1"])
+
+	sel.Close()
+	reg.RemoveChild(ids["a"])
+	assert.True(sel.IsSelected(ids["a1"]), "A closed SelectionSet shouldn't follow the registry")
+}
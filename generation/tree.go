@@ -0,0 +1,906 @@
+package generation
+
+import (
+	"image/color"
+	"log"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+var _ fyne.CanvasObject = (*Tree)(nil)
+var _ fyne.Focusable = (*treeFocus)(nil)
+
+// Tree is a widget.Tree that manages IDs through a TreeModelRegistry, like the trees created by fynehelper, but
+// configured at runtime with TreeOption values instead of generated. Features are enabled with options when the tree is
+// created, and handlers may be set with options or by setting their fields before the tree is shown.
+type Tree struct {
+	widget.Tree
+	*TreeModelRegistry
+
+	OnTapped           func(id widget.TreeNodeID, model TreeModel, event *fyne.PointEvent)    // OnTapped is called when a node is tapped, after it's selected.
+	OnDoubleTapped     func(id widget.TreeNodeID, model TreeModel, event *fyne.PointEvent)    // OnDoubleTapped is called when a node is double tapped. It must be set before the tree is shown, since single taps are delayed on nodes that could be double tapped.
+	OnTappedSecondary  func(id widget.TreeNodeID, model TreeModel, event *fyne.PointEvent)    // OnTappedSecondary is called when a node is secondary tapped, before any context menu is shown.
+	OnMouseIn          func(id widget.TreeNodeID, model TreeModel, event *desktop.MouseEvent) // OnMouseIn is called when the pointer enters a node.
+	OnMouseMoved       func(id widget.TreeNodeID, model TreeModel, event *desktop.MouseEvent) // OnMouseMoved is called when the pointer moves over a node.
+	OnMouseOut         func(id widget.TreeNodeID, model TreeModel)                            // OnMouseOut is called when the pointer leaves a node.
+	OnMouseDown        func(id widget.TreeNodeID, model TreeModel, event *desktop.MouseEvent) // OnMouseDown is called when a mouse button is pressed on a node, with the button and modifier.
+	OnMouseUp          func(id widget.TreeNodeID, model TreeModel, event *desktop.MouseEvent) // OnMouseUp is called when a mouse button is released on a node, with the button and modifier.
+	OnSelected         func(id widget.TreeNodeID)                                             // OnSelected is called when a node is selected without multi-selection. It replaces widget.Tree's field so the selection can be tracked.
+	OnUnselected       func(id widget.TreeNodeID)                                             // OnUnselected is called when a node is unselected without multi-selection. It replaces widget.Tree's field so the selection can be tracked.
+	OnSelectionChanged func(selected []widget.TreeNodeID)                                     // OnSelectionChanged is called with the full selection, in depth-first order, whenever it changes with multi-selection.
+	OnCheckChanged     func(id widget.TreeNodeID, model TreeModel, state CheckState)          // OnCheckChanged is called for every node whose check state changes, including ancestors and descendants of a checked node.
+	OnDrop             func(id widget.TreeNodeID, model TreeModel, target DropTarget) bool    // OnDrop is called before a dragged node is moved, and may return false to veto the drop.
+	OnActivate         func(id widget.TreeNodeID, model TreeModel)                            // OnActivate is called when Enter is pressed on the current node. Branches are opened or closed instead if it's nil.
+	MenuFor            func(id widget.TreeNodeID, model TreeModel) *fyne.Menu                 // MenuFor provides the context menu shown when a node is secondary tapped. Items from models implementing ContextMenuTreeModel are added after it.
+	HighlightHover     bool                                                                   // HighlightHover shows a highlight behind the node under the pointer, for nodes that handle hover events. Other nodes show widget.Tree's highlight. It's enabled by NewTree.
+	KeyActions         map[fyne.KeyName]func(current widget.TreeNodeID)                       // KeyActions maps keys to the action taken on the current node while the tree is focused. NewTree fills it with the default actions for the enabled features, which may be replaced or removed.
+	TypeAhead          func(prefix string)                                                    // TypeAhead is called with the text typed so far while the tree is focused. It defaults to SelectPrefix with keyboard navigation.
+
+	checkable    bool
+	multiSelect  bool
+	draggable    bool
+	editable     bool
+	contextMenu  bool
+	keyboard     bool
+	tooltips     bool
+	nodeContent  NodeContentFactory
+	highlight    func(text string) [][2]int // highlight finds the rune ranges of a node's text to emphasize, like a SearchTree's matches.
+	errorHandler func(err error)
+	idStrategy   IDStrategy
+	self         fyne.Widget // self is the widget that's displayed, which may embed this tree.
+	focusTarget  *treeFocus  // focusTarget takes keyboard focus for the tree, and is only created if the tree handles keys.
+	unlisten     func()      // unlisten removes the tree's registry listener.
+	roots        []TreeModel
+
+	nodeMux    sync.RWMutex
+	nodes      map[widget.TreeNodeID]*treeNode
+	checks     *CheckSet
+	selection  *SelectionSet
+	dropTarget DropTarget                      // dropTarget is guarded by nodeMux.
+	current    widget.TreeNodeID               // current is the last node tapped or moved to, which key actions apply to. It's guarded by nodeMux.
+	renaming   widget.TreeNodeID               // renaming is guarded by nodeMux.
+	selected   widget.TreeNodeID               // selected is guarded by nodeMux.
+	scrollTop  widget.TreeNodeID               // scrollTop is scrolled to the top once the tree is laid out. It's guarded by nodeMux.
+	filter     func(id widget.TreeNodeID) bool // filter is guarded by nodeMux.
+	typeAhead  TypeAheadBuffer
+}
+
+// NewTree creates a tree configured by options, and adds the roots given with WithRoots to its registry. Roots that
+// can't be added are passed to the error handler.
+func NewTree(options ...TreeOption) *Tree {
+	tree := newTree(options)
+	for _, root := range tree.roots {
+		if _, err := tree.AddChild(ModelRoot, root); err != nil {
+			tree.errorHandler(err)
+		}
+	}
+	tree.roots = nil
+	tree.ExtendBaseWidget(tree.self)
+	return tree
+}
+
+// NewTreeE creates a tree like NewTree, but returns the first error from adding roots instead of passing it to the
+// error handler.
+func NewTreeE(options ...TreeOption) (*Tree, error) {
+	tree := newTree(options)
+	for _, root := range tree.roots {
+		if _, err := tree.AddChild(ModelRoot, root); err != nil {
+			return nil, err
+		}
+	}
+	tree.roots = nil
+	tree.ExtendBaseWidget(tree.self)
+	return tree, nil
+}
+
+func newTree(options []TreeOption) *Tree {
+	tree := &Tree{
+		HighlightHover: true,
+		nodes:          map[widget.TreeNodeID]*treeNode{},
+	}
+	tree.Tree = widget.Tree{
+		ChildUIDs: tree.childIDs,
+		CreateNode: func(bool) fyne.CanvasObject {
+			return newTreeNode(tree)
+		},
+		IsBranch: tree.hasChildren,
+		UpdateNode: func(id widget.TreeNodeID, isBranch bool, obj fyne.CanvasObject) {
+			node := asTreeNode(obj)
+			if node == nil {
+				return
+			}
+			model := tree.TreeModelRegistry.Node(id)
+			if model == nil {
+				return
+			}
+			tree.bindNode(id, node)
+			node.update(id, model)
+		},
+		OnBranchOpened: func(id widget.TreeNodeID) {
+			// The registry isn't known until the options are applied.
+			tree.Load(id)
+		},
+		OnSelected:   tree.nodeSelected,
+		OnUnselected: tree.nodeUnselected,
+	}
+	tree.KeyActions = map[fyne.KeyName]func(widget.TreeNodeID){}
+	for _, option := range options {
+		option(tree)
+	}
+	if tree.TreeModelRegistry == nil {
+		tree.TreeModelRegistry = NewTreeModelRegistry()
+	}
+	if tree.idStrategy != nil {
+		tree.SetIDStrategy(tree.idStrategy)
+	}
+	if tree.self == nil {
+		tree.self = tree
+	}
+	tree.unlisten = tree.AddListener(tree.registryChanged)
+	tree.checks = NewCheckSet(tree.TreeModelRegistry)
+	tree.checks.OnChanged = tree.checksChanged
+	tree.selection = NewSelectionSet(tree.TreeModelRegistry)
+	tree.selection.OnChanged = tree.selectionChanged
+	if tree.errorHandler == nil {
+		tree.errorHandler = func(err error) {
+			log.Printf("Error in Tree: %v\n", err)
+		}
+	}
+	if tree.keyboard {
+		tree.KeyActions[fyne.KeyUp] = tree.SelectPrevious
+		tree.KeyActions[fyne.KeyDown] = tree.SelectNext
+		tree.KeyActions[fyne.KeyLeft] = tree.CollapseOrSelectParent
+		tree.KeyActions[fyne.KeyRight] = tree.ExpandOrSelectChild
+		tree.KeyActions[fyne.KeyHome] = func(widget.TreeNodeID) {
+			tree.SelectFirst()
+		}
+		tree.KeyActions[fyne.KeyEnd] = func(widget.TreeNodeID) {
+			tree.SelectLast()
+		}
+		tree.KeyActions[fyne.KeyReturn] = tree.Activate
+		tree.KeyActions[fyne.KeyEnter] = tree.Activate
+		tree.KeyActions[fyne.KeyDelete] = tree.RemoveAndSelectNext
+		tree.TypeAhead = tree.SelectPrefix
+	}
+	if tree.editable {
+		tree.KeyActions[fyne.KeyF2] = func(id widget.TreeNodeID) {
+			if tree.CanRename(id) {
+				tree.StartRename(id)
+			}
+		}
+	}
+	if tree.keyboard || tree.editable {
+		tree.focusTarget = newTreeFocus(tree)
+	}
+	return tree
+}
+
+// Close stops the tree following changes to its registry, so a tree sharing a registry that outlives it can be
+// released. The tree shouldn't be shown after it's closed.
+func (t *Tree) Close() {
+	t.unlisten()
+	t.checks.Close()
+	t.selection.Close()
+}
+
+// showContextMenu shows the menu for id at the position of event, if there are any items for it.
+func (t *Tree) showContextMenu(id widget.TreeNodeID, event *fyne.PointEvent) {
+	var menu *fyne.Menu
+	if t.MenuFor != nil {
+		menu = t.MenuFor(id, t.Node(id))
+	}
+	menu = ContextMenu(t.Node(id), menu)
+	if menu == nil {
+		return
+	}
+	c := fyne.CurrentApp().Driver().CanvasForObject(t.self)
+	if c == nil {
+		return
+	}
+	widget.ShowPopUpMenuAtPosition(menu, c, event.AbsolutePosition)
+}
+
+// SetChecked checks or unchecks id and all of its descendants, and updates its ancestors to match.
+func (t *Tree) SetChecked(id widget.TreeNodeID, checked bool) {
+	t.checks.SetChecked(id, checked)
+}
+
+// CheckState returns the check state of id.
+func (t *Tree) CheckState(id widget.TreeNodeID) CheckState {
+	return t.checks.State(id)
+}
+
+// CheckedIDs returns the IDs of all checked nodes, in depth-first order.
+func (t *Tree) CheckedIDs() []widget.TreeNodeID {
+	return t.checks.CheckedIDs()
+}
+
+func (t *Tree) checksChanged(changed []widget.TreeNodeID) {
+	for _, id := range changed {
+		t.RefreshNode(id)
+		if t.OnCheckChanged != nil {
+			t.OnCheckChanged(id, t.Node(id), t.checks.State(id))
+		}
+	}
+}
+
+// SelectedIDs returns the selected IDs in depth-first order.
+func (t *Tree) SelectedIDs() []widget.TreeNodeID {
+	if t.multiSelect {
+		return t.selection.SelectedIDs()
+	}
+	t.nodeMux.RLock()
+	defer t.nodeMux.RUnlock()
+	if t.selected == "" {
+		return nil
+	}
+	return []widget.TreeNodeID{t.selected}
+}
+
+// IsSelected returns whether id is selected.
+func (t *Tree) IsSelected(id widget.TreeNodeID) bool {
+	if t.multiSelect {
+		return t.selection.IsSelected(id)
+	}
+	t.nodeMux.RLock()
+	defer t.nodeMux.RUnlock()
+	return t.selected != "" && t.selected == id
+}
+
+// SelectAll selects every node in the tree, including nodes in closed branches. It does nothing without
+// multi-selection.
+func (t *Tree) SelectAll() {
+	if t.multiSelect {
+		t.selection.SelectAll()
+	}
+}
+
+// ClearSelection unselects every node.
+func (t *Tree) ClearSelection() {
+	if t.multiSelect {
+		t.selection.Clear()
+		return
+	}
+	t.UnselectAll()
+}
+
+// selectWithModifier applies desktop selection semantics: control or super toggles a node, shift selects a range of
+// displayed nodes, and anything else selects only the tapped node.
+func (t *Tree) selectWithModifier(id widget.TreeNodeID, modifier desktop.Modifier) {
+	switch {
+	case modifier&(desktop.ControlModifier|desktop.SuperModifier) != 0:
+		t.selection.Toggle(id)
+	case modifier&desktop.ShiftModifier != 0:
+		t.selection.SelectRange(id, t.visibleIDs())
+	default:
+		t.selection.Select(id)
+	}
+}
+
+func (t *Tree) selectionChanged(changed []widget.TreeNodeID) {
+	for _, id := range changed {
+		t.RefreshNode(id)
+	}
+	if t.OnSelectionChanged != nil {
+		t.OnSelectionChanged(t.selection.SelectedIDs())
+	}
+}
+
+func (t *Tree) nodeSelected(id widget.TreeNodeID) {
+	t.nodeMux.Lock()
+	t.selected = id
+	t.nodeMux.Unlock()
+	if t.OnSelected != nil {
+		t.OnSelected(id)
+	}
+}
+
+func (t *Tree) nodeUnselected(id widget.TreeNodeID) {
+	t.nodeMux.Lock()
+	if t.selected == id {
+		t.selected = ""
+	}
+	t.nodeMux.Unlock()
+	if t.OnUnselected != nil {
+		t.OnUnselected(id)
+	}
+}
+
+// Current returns the node that key actions apply to, which is the last node tapped or moved to.
+func (t *Tree) Current() widget.TreeNodeID {
+	t.nodeMux.RLock()
+	defer t.nodeMux.RUnlock()
+	return t.current
+}
+
+func (t *Tree) setCurrent(id widget.TreeNodeID) {
+	t.nodeMux.Lock()
+	defer t.nodeMux.Unlock()
+	t.current = id
+}
+
+// TypedKey runs the action in KeyActions for the key on the current node.
+func (t *Tree) TypedKey(event *fyne.KeyEvent) {
+	if action, ok := t.KeyActions[event.Name]; ok && action != nil {
+		action(t.Current())
+	}
+}
+
+// TypedRune adds r to the type-ahead prefix and passes it to TypeAhead.
+func (t *Tree) TypedRune(r rune) {
+	if t.TypeAhead == nil {
+		return
+	}
+	t.TypeAhead(t.typeAhead.Add(r, time.Now()))
+}
+
+// CreateRenderer adds the tree's focus target to widget.Tree's renderer, so the tree is only focusable if it handles
+// keys.
+func (t *Tree) CreateRenderer() fyne.WidgetRenderer {
+	render := t.Tree.CreateRenderer()
+	if t.focusTarget == nil {
+		return render
+	}
+	return &treeRenderer{WidgetRenderer: render, focus: t.focusTarget}
+}
+
+type treeRenderer struct {
+	fyne.WidgetRenderer
+	focus fyne.CanvasObject
+}
+
+func (r *treeRenderer) Objects() []fyne.CanvasObject {
+	return append(append([]fyne.CanvasObject{}, r.WidgetRenderer.Objects()...), r.focus)
+}
+
+// treeFocus is an invisible widget that takes keyboard focus for a Tree, and passes typed keys to it.
+type treeFocus struct {
+	widget.BaseWidget
+	tree *Tree
+}
+
+func newTreeFocus(tree *Tree) *treeFocus {
+	focus := &treeFocus{tree: tree}
+	focus.ExtendBaseWidget(focus)
+	return focus
+}
+
+func (f *treeFocus) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+func (f *treeFocus) FocusGained() {
+}
+
+func (f *treeFocus) FocusLost() {
+}
+
+func (f *treeFocus) TypedKey(event *fyne.KeyEvent) {
+	f.tree.TypedKey(event)
+}
+
+func (f *treeFocus) TypedRune(r rune) {
+	f.tree.TypedRune(r)
+}
+
+// MoveTo makes id the current node, and selects and scrolls to it.
+func (t *Tree) MoveTo(id widget.TreeNodeID) {
+	if id == ModelRoot {
+		return
+	}
+	t.setCurrent(id)
+	if t.multiSelect {
+		t.selection.Select(id)
+		t.ScrollTo(id)
+		return
+	}
+	t.Select(id)
+}
+
+// SelectPrevious moves to the displayed node before id.
+func (t *Tree) SelectPrevious(id widget.TreeNodeID) {
+	t.MoveTo(Neighbor(t.visibleIDs(), id, -1))
+}
+
+// SelectNext moves to the displayed node after id.
+func (t *Tree) SelectNext(id widget.TreeNodeID) {
+	t.MoveTo(Neighbor(t.visibleIDs(), id, 1))
+}
+
+// SelectFirst moves to the first node in the tree.
+func (t *Tree) SelectFirst() {
+	if roots := t.childIDs(ModelRoot); len(roots) > 0 {
+		t.MoveTo(roots[0])
+	}
+}
+
+// SelectLast moves to the last displayed node in the tree.
+func (t *Tree) SelectLast() {
+	if visible := t.visibleIDs(); len(visible) > 0 {
+		t.MoveTo(visible[len(visible)-1])
+	}
+}
+
+// ExpandOrSelectChild opens id if it's a closed branch, or moves to its first child if it's already open.
+func (t *Tree) ExpandOrSelectChild(id widget.TreeNodeID) {
+	if !t.hasChildren(id) {
+		return
+	}
+	if !t.IsBranchOpen(id) {
+		t.OpenBranch(id)
+		return
+	}
+	t.MoveTo(t.childIDs(id)[0])
+}
+
+// CollapseOrSelectParent closes id if it's an open branch, or moves to its parent otherwise.
+func (t *Tree) CollapseOrSelectParent(id widget.TreeNodeID) {
+	if t.hasChildren(id) && t.IsBranchOpen(id) {
+		t.CloseBranch(id)
+		return
+	}
+	t.MoveTo(t.Parent(id))
+}
+
+// Activate calls OnActivate for id, or opens or closes it if it's a branch and OnActivate is nil.
+func (t *Tree) Activate(id widget.TreeNodeID) {
+	if id == ModelRoot {
+		return
+	}
+	if t.OnActivate != nil {
+		t.OnActivate(id, t.Node(id))
+		return
+	}
+	if t.hasChildren(id) {
+		t.ToggleBranch(id)
+	}
+}
+
+// RemoveAndSelectNext removes id and its descendants, and moves to the node displayed in its place.
+func (t *Tree) RemoveAndSelectNext(id widget.TreeNodeID) {
+	if id == ModelRoot {
+		return
+	}
+	index := -1
+	for i, vid := range t.visibleIDs() {
+		if vid == id {
+			index = i
+			break
+		}
+	}
+	t.RemoveChild(id)
+	visible := t.visibleIDs()
+	if index < 0 || len(visible) == 0 {
+		return
+	}
+	if index >= len(visible) {
+		index = len(visible) - 1
+	}
+	t.MoveTo(visible[index])
+}
+
+// SelectPrefix moves to the next displayed node whose display string starts with prefix, ignoring case.
+func (t *Tree) SelectPrefix(prefix string) {
+	t.MoveTo(t.MatchPrefix(t.visibleIDs(), t.Current(), prefix))
+}
+
+func (t *Tree) focus() {
+	if t.focusTarget == nil {
+		return
+	}
+	if c := fyne.CurrentApp().Driver().CanvasForObject(t.focusTarget); c != nil {
+		c.Focus(t.focusTarget)
+	}
+}
+
+// StartRename replaces the label of id with an entry, so the user can rename it. The entry commits on Enter or when it
+// loses focus, and cancels on Escape. Only models implementing RenamableTreeModel can be renamed, and only if the tree
+// was created with WithRename.
+func (t *Tree) StartRename(id widget.TreeNodeID) error {
+	if !t.editable || !t.CanRename(id) {
+		return ErrNotRenamable
+	}
+	t.nodeMux.Lock()
+	prev := t.renaming
+	t.renaming = id
+	t.nodeMux.Unlock()
+	t.RefreshNode(prev)
+	t.ScrollTo(id)
+	t.RefreshNode(id)
+	return nil
+}
+
+func (t *Tree) isRenaming(id widget.TreeNodeID) bool {
+	t.nodeMux.RLock()
+	defer t.nodeMux.RUnlock()
+	return t.renaming != "" && t.renaming == id
+}
+
+// commitRename renames the node being edited, and stops editing unless the model returns an error.
+func (t *Tree) commitRename(name string) error {
+	t.nodeMux.RLock()
+	id := t.renaming
+	t.nodeMux.RUnlock()
+	if err := t.Rename(id, name); err != nil {
+		return err
+	}
+	t.stopRename()
+	return nil
+}
+
+func (t *Tree) stopRename() {
+	t.nodeMux.Lock()
+	id := t.renaming
+	t.renaming = ""
+	t.nodeMux.Unlock()
+	t.RefreshNode(id)
+}
+
+// dragged updates the drop target to the node under pos, if id can be dropped there.
+func (t *Tree) dragged(id widget.TreeNodeID, pos fyne.Position) {
+	target := t.dropTargetAt(pos)
+	if target.Position != DropNone && t.CanDrop(id, target) != nil {
+		target = DropTarget{}
+	}
+	t.setDropTarget(target)
+}
+
+// dragEnd moves id to the current drop target, unless OnDrop vetoes it.
+func (t *Tree) dragEnd(id widget.TreeNodeID) {
+	target := t.setDropTarget(DropTarget{})
+	if target.Position == DropNone {
+		return
+	}
+	if t.OnDrop != nil && !t.OnDrop(id, t.Node(id), target) {
+		return
+	}
+	if err := t.Drop(id, target); err != nil {
+		t.errorHandler(err)
+		return
+	}
+	if target.Position == DropInto {
+		t.OpenBranch(target.ID)
+	}
+	t.Refresh()
+}
+
+// dropTargetAt finds the displayed node under the absolute position pos.
+func (t *Tree) dropTargetAt(pos fyne.Position) DropTarget {
+	driver := fyne.CurrentApp().Driver()
+	treePos := driver.AbsolutePositionForObject(t.self)
+	treeSize := t.Size()
+	within := func(p fyne.Position, origin fyne.Position, size fyne.Size) bool {
+		return p.X >= origin.X && p.Y >= origin.Y && p.X <= origin.X+size.Width && p.Y <= origin.Y+size.Height
+	}
+	if !within(pos, treePos, treeSize) {
+		return DropTarget{}
+	}
+	t.nodeMux.RLock()
+	defer t.nodeMux.RUnlock()
+	for id, node := range t.nodes {
+		// Nodes that have scrolled out of the tree are still bound, but aren't positioned within it.
+		nodePos := driver.AbsolutePositionForObject(node.self)
+		if !node.Visible() || !within(nodePos, treePos, treeSize) || !within(pos, nodePos, node.Size()) {
+			continue
+		}
+		return DropTarget{ID: id, Position: DropPositionAt(pos.Y-nodePos.Y, node.Size().Height)}
+	}
+	return DropTarget{}
+}
+
+// setDropTarget replaces the drop target and returns the previous one, refreshing the nodes that show it.
+func (t *Tree) setDropTarget(target DropTarget) DropTarget {
+	t.nodeMux.Lock()
+	prev := t.dropTarget
+	t.dropTarget = target
+	t.nodeMux.Unlock()
+	if prev != target {
+		t.RefreshNode(prev.ID)
+		t.RefreshNode(target.ID)
+	}
+	return prev
+}
+
+// dropPositionFor returns the drop indicator position that id's node should display.
+func (t *Tree) dropPositionFor(id widget.TreeNodeID) DropPosition {
+	t.nodeMux.RLock()
+	defer t.nodeMux.RUnlock()
+	if t.dropTarget.ID != id {
+		return DropNone
+	}
+	return t.dropTarget.Position
+}
+
+// Reveal opens every ancestor of id, then selects and scrolls to it.
+func (t *Tree) Reveal(id widget.TreeNodeID) {
+	if t.Node(id) == nil {
+		return
+	}
+	for _, ancestor := range t.Ancestors(id) {
+		if !t.IsBranchOpen(ancestor) {
+			t.OpenBranch(ancestor)
+		}
+	}
+	t.MoveTo(id)
+}
+
+// ExpandAll opens every branch with a depth less than maxDepth, where roots have a depth of 0. A negative maxDepth opens
+// branches at any depth. Branches that are already open are left open.
+func (t *Tree) ExpandAll(maxDepth int) {
+	// Opening a lazy branch can find more branches, so this repeats until none are opened.
+	for opened := true; opened; {
+		opened = false
+		for _, id := range t.Branches(maxDepth) {
+			if !t.IsBranchOpen(id) {
+				t.OpenBranch(id)
+				opened = true
+			}
+		}
+	}
+}
+
+// CollapseAll closes every branch.
+func (t *Tree) CollapseAll() {
+	t.ExpandTo(0)
+}
+
+// ExpandTo shows depth levels of the tree, opening every branch with a depth less than depth and closing the rest.
+// Roots have a depth of 0, and a negative depth opens every branch.
+func (t *Tree) ExpandTo(depth int) {
+	t.setOpen(func(id widget.TreeNodeID) bool {
+		return depth < 0 || len(t.Ancestors(id)) < depth
+	})
+}
+
+// setOpen opens the branches that open returns true for, and closes the rest. Opening a lazy branch can find more
+// branches, so this repeats until no new ones are found.
+func (t *Tree) setOpen(open func(id widget.TreeNodeID) bool) {
+	seen := map[widget.TreeNodeID]bool{}
+	for found := true; found; {
+		found = false
+		for _, id := range t.Branches(-1) {
+			if seen[id] {
+				continue
+			}
+			seen[id], found = true, true
+			switch shouldOpen, isOpen := open(id), t.IsBranchOpen(id); {
+			case shouldOpen && !isOpen:
+				t.OpenBranch(id)
+			case !shouldOpen && isOpen:
+				t.CloseBranch(id)
+			}
+		}
+	}
+}
+
+// SetFilter hides every node that keep returns false for, along with its descendants, and a nil keep shows every node
+// again. keep is called while the registry is locked for reading, so it must not change the registry.
+func (t *Tree) SetFilter(keep func(id widget.TreeNodeID) bool) {
+	t.nodeMux.Lock()
+	t.filter = keep
+	t.nodeMux.Unlock()
+	t.Refresh()
+}
+
+func (t *Tree) keep(id widget.TreeNodeID) bool {
+	t.nodeMux.RLock()
+	keep := t.filter
+	t.nodeMux.RUnlock()
+	return keep == nil || keep(id)
+}
+
+// childIDs returns the children of id that aren't filtered.
+func (t *Tree) childIDs(id widget.TreeNodeID) []widget.TreeNodeID {
+	children := t.Children(id)
+	kept := children[:0:0]
+	for _, child := range children {
+		if t.keep(child) {
+			kept = append(kept, child)
+		}
+	}
+	return kept
+}
+
+func (t *Tree) hasChildren(id widget.TreeNodeID) bool {
+	if !t.IsLoaded(id) {
+		return t.HasChildren(id)
+	}
+	return len(t.childIDs(id)) > 0
+}
+
+// visibleIDs returns the displayed nodes that aren't filtered, see TreeModelRegistry.VisibleIDs.
+func (t *Tree) visibleIDs() []widget.TreeNodeID {
+	visible := t.VisibleIDs(func(id widget.TreeNodeID) bool {
+		return t.keep(id) && t.IsBranchOpen(id)
+	})
+	kept := visible[:0]
+	for _, id := range visible {
+		if t.keep(id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// SaveViewState captures the open branches, the selection and the node scrolled to the top of the tree. The state can
+// only be restored between runs if the registry creates stable IDs, see TreeModelRegistry.SetIDStrategy.
+func (t *Tree) SaveViewState() ViewState {
+	var state ViewState
+	for _, id := range t.Branches(-1) {
+		if t.IsBranchOpen(id) {
+			state.Open = append(state.Open, id)
+		}
+	}
+	state.Selected = t.SelectedIDs()
+	state.ScrollTop = t.topNode()
+	return state
+}
+
+// RestoreViewState opens and closes branches, selects nodes and scrolls to match state. IDs that aren't in the registry
+// are ignored.
+func (t *Tree) RestoreViewState(state ViewState) {
+	open := map[widget.TreeNodeID]bool{}
+	for _, id := range state.Open {
+		open[id] = true
+	}
+	t.setOpen(func(id widget.TreeNodeID) bool {
+		return open[id]
+	})
+	var selected []widget.TreeNodeID
+	for _, id := range state.Selected {
+		if t.Node(id) != nil {
+			selected = append(selected, id)
+		}
+	}
+	switch {
+	case t.multiSelect:
+		t.selection.SelectIDs(selected...)
+	case len(selected) == 0:
+		t.UnselectAll()
+	default:
+		t.Select(selected[0])
+	}
+	if len(selected) > 0 {
+		t.setCurrent(selected[0])
+	}
+	if t.Node(state.ScrollTop) == nil {
+		return
+	}
+	if t.Size().IsZero() {
+		t.nodeMux.Lock()
+		t.scrollTop = state.ScrollTop
+		t.nodeMux.Unlock()
+		return
+	}
+	t.scrollToTop(state.ScrollTop)
+}
+
+// Resize lays out the tree, then applies any scrolling left by RestoreViewState.
+func (t *Tree) Resize(size fyne.Size) {
+	t.Tree.Resize(size)
+	t.nodeMux.Lock()
+	id := t.scrollTop
+	t.scrollTop = ""
+	t.nodeMux.Unlock()
+	if id != "" {
+		t.scrollToTop(id)
+	}
+}
+
+// scrollToTop scrolls id to the top of the tree. widget.Tree.ScrollTo only scrolls far enough to show a node, so it's
+// approached from the bottom.
+func (t *Tree) scrollToTop(id widget.TreeNodeID) {
+	t.ScrollToBottom()
+	t.ScrollTo(id)
+}
+
+// topNode finds the first displayed node that isn't scrolled above the top of the tree.
+func (t *Tree) topNode() widget.TreeNodeID {
+	driver := fyne.CurrentApp().Driver()
+	treePos := driver.AbsolutePositionForObject(t.self)
+	order := t.visibleIDs()
+	t.nodeMux.RLock()
+	defer t.nodeMux.RUnlock()
+	for _, id := range order {
+		node, ok := t.nodes[id]
+		if !ok {
+			continue
+		}
+		// Nodes that widget.Tree has released are still bound, but aren't found on the canvas.
+		nodePos := driver.AbsolutePositionForObject(node.self)
+		if nodePos == (fyne.Position{}) && treePos != (fyne.Position{}) {
+			continue
+		}
+		if nodePos.Y+node.Size().Height > treePos.Y {
+			return id
+		}
+	}
+	return ""
+}
+
+// RefreshNode updates only the node displaying id, if it's currently displayed.
+// This is called automatically when the registry is notified of an update.
+func (t *Tree) RefreshNode(id widget.TreeNodeID) {
+	t.nodeMux.RLock()
+	node, ok := t.nodes[id]
+	t.nodeMux.RUnlock()
+	if !ok {
+		return
+	}
+	model := t.Node(id)
+	if model == nil {
+		return
+	}
+	node.update(id, model)
+}
+
+// bindNode tracks which node is displaying id, since widget.Tree reuses nodes as it scrolls.
+func (t *Tree) bindNode(id widget.TreeNodeID, node *treeNode) {
+	prevID := node.nodeID()
+	t.nodeMux.Lock()
+	defer t.nodeMux.Unlock()
+	if t.nodes[prevID] == node {
+		delete(t.nodes, prevID)
+	}
+	t.nodes[id] = node
+}
+
+func (t *Tree) registryChanged(event RegistryEvent) {
+	switch event.Kind {
+	case NodeUpdated:
+		t.RefreshNode(event.ID)
+	case NodeRemoved:
+		t.forgetRemoved()
+	}
+}
+
+// forgetRemoved stops tracking nodes that are no longer registered. Only one event is sent when a node is removed,
+// but its descendants are removed with it.
+func (t *Tree) forgetRemoved() {
+	t.nodeMux.RLock()
+	tracked := []widget.TreeNodeID{t.renaming, t.current, t.selected}
+	for id := range t.nodes {
+		tracked = append(tracked, id)
+	}
+	t.nodeMux.RUnlock()
+	removed := map[widget.TreeNodeID]bool{}
+	for _, id := range tracked {
+		if id != ModelRoot && t.Node(id) == nil {
+			removed[id] = true
+		}
+	}
+	t.nodeMux.Lock()
+	defer t.nodeMux.Unlock()
+	for id := range removed {
+		delete(t.nodes, id)
+	}
+	if removed[t.renaming] {
+		t.renaming = ""
+	}
+	if removed[t.current] {
+		t.current = ""
+	}
+	if removed[t.selected] {
+		t.selected = ""
+	}
+}
+
+func (t *Tree) AddChild(parentID widget.TreeNodeID, data TreeModel) (widget.TreeNodeID, error) {
+	defer t.Refresh()
+	return t.TreeModelRegistry.AddChild(parentID, data)
+}
+
+func (t *Tree) RemoveChild(dataID widget.TreeNodeID) {
+	defer t.Refresh()
+	t.TreeModelRegistry.RemoveChild(dataID)
+}
+
+func (t *Tree) MoveChild(nodeID widget.TreeNodeID, newParentID widget.TreeNodeID, index int) error {
+	defer t.Refresh()
+	return t.TreeModelRegistry.MoveChild(nodeID, newParentID, index)
+}
@@ -0,0 +1,273 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+// buildTree creates a tree from options with the models from buildModels, and shows it in a window. Every branch is
+// opened so every node is displayed.
+func buildTree(t *testing.T, options ...TreeOption) (*Tree, map[string]widget.TreeNodeID, fyne.Window) {
+	test.NewApp()
+	root := buildModels(t)
+	tree := NewTree(append([]TreeOption{WithIDStrategy(PathStrategy), WithRoots(root)}, options...)...)
+	tree.ExpandAll(-1)
+	w := test.NewWindow(tree)
+	w.Resize(fyne.NewSize(300, 400))
+	ids := map[string]widget.TreeNodeID{
+		"root": "root",
+		"a":    "root/a",
+		"a1":   "root/a/a1",
+		"a2":   "root/a/a2",
+		"b":    "root/b",
+	}
+	return tree, ids, w
+}
+
+func displayedNode(t *testing.T, tree *Tree, id widget.TreeNodeID) *treeNode {
+	tree.nodeMux.RLock()
+	defer tree.nodeMux.RUnlock()
+	node, ok := tree.nodes[id]
+	if !ok {
+		t.Fatalf("Node %s is not displayed", id)
+	}
+	return node
+}
+
+func TestTree_Tapped(t *testing.T) {
+	assert := testify.New(t)
+	var tapped []widget.TreeNodeID
+	var tappedModels []TreeModel
+	var selected []widget.TreeNodeID
+	tree, ids, w := buildTree(t,
+		WithOnTapped(func(id widget.TreeNodeID, model TreeModel, _ *fyne.PointEvent) {
+			tapped = append(tapped, id)
+			tappedModels = append(tappedModels, model)
+		}),
+		WithOnSelected(func(id widget.TreeNodeID) {
+			selected = append(selected, id)
+		}, nil),
+	)
+	defer w.Close()
+
+	test.Tap(displayedNode(t, tree, ids["a1"]).self.(fyne.Tappable))
+	test.Tap(displayedNode(t, tree, ids["b"]).self.(fyne.Tappable))
+	assert.Equal([]widget.TreeNodeID{ids["a1"], ids["b"]}, tapped)
+	assert.Equal([]TreeModel{tree.Node(ids["a1"]), tree.Node(ids["b"])}, tappedModels)
+	assert.Equal([]widget.TreeNodeID{ids["a1"], ids["b"]}, selected, "Taps should select the node")
+	assert.Equal([]widget.TreeNodeID{ids["b"]}, tree.SelectedIDs())
+	assert.True(tree.IsSelected(ids["b"]))
+	assert.Equal(ids["b"], tree.Current())
+
+	tree.RemoveChild(ids["b"])
+	assert.Empty(tree.SelectedIDs(), "Removing the selected node should clear the selection")
+	node := displayedNode(t, tree, ids["a"]).self
+	_, isDoubleTappable := node.(fyne.DoubleTappable)
+	assert.False(isDoubleTappable, "Nodes shouldn't delay taps unless double taps are handled")
+	_, isHoverable := node.(desktop.Hoverable)
+	assert.False(isHoverable, "Nodes shouldn't handle hover events without tooltips or hover handlers")
+	_, isMouseable := node.(desktop.Mouseable)
+	assert.False(isMouseable, "Nodes shouldn't handle mouse buttons without multi-select or mouse handlers")
+	_, isDraggable := node.(fyne.Draggable)
+	assert.False(isDraggable, "Nodes shouldn't stop touch scrolling without drag and drop")
+	_, isFocusable := fyne.CanvasObject(tree).(fyne.Focusable)
+	assert.False(isFocusable, "Trees shouldn't take focus without keyboard navigation")
+	assert.Nil(tree.focusTarget)
+}
+
+func TestTree_RemoveAncestor(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, w := buildTree(t, WithRename())
+	defer w.Close()
+
+	test.Tap(displayedNode(t, tree, ids["a1"]).self.(fyne.Tappable))
+	assert.NoError(tree.StartRename(ids["a"]))
+	tree.RemoveChild(ids["root"])
+	assert.Empty(tree.SelectedIDs(), "Removing an ancestor of the selected node should clear the selection")
+	assert.Empty(tree.Current())
+	assert.False(tree.isRenaming(ids["a"]))
+	tree.nodeMux.RLock()
+	defer tree.nodeMux.RUnlock()
+	assert.Empty(tree.nodes, "Nodes bound to removed descendants should be forgotten")
+}
+
+func TestTree_MultiSelect(t *testing.T) {
+	assert := testify.New(t)
+	var reported []widget.TreeNodeID
+	tree, ids, w := buildTree(t, WithMultiSelect(), WithOnSelectionChanged(func(selected []widget.TreeNodeID) {
+		reported = selected
+	}))
+	defer w.Close()
+
+	tapWith := func(id widget.TreeNodeID, modifier desktop.Modifier) {
+		node := displayedNode(t, tree, id)
+		node.self.(desktop.Mouseable).MouseDown(&desktop.MouseEvent{Button: desktop.MouseButtonPrimary, Modifier: modifier})
+		node.Tapped(&fyne.PointEvent{})
+	}
+	tapWith(ids["a"], 0)
+	tapWith(ids["b"], desktop.ControlModifier)
+	assert.Equal([]widget.TreeNodeID{ids["a"], ids["b"]}, reported)
+	tapWith(ids["a1"], desktop.ShiftModifier)
+	assert.Equal([]widget.TreeNodeID{ids["a1"], ids["a2"], ids["b"]}, tree.SelectedIDs())
+	assert.True(displayedNode(t, tree, ids["a2"]).selected, "Selected nodes should be highlighted")
+
+	tree.ClearSelection()
+	assert.Empty(tree.SelectedIDs())
+	tree.SelectAll()
+	assert.Len(tree.SelectedIDs(), 5)
+}
+
+func TestTree_Keyboard(t *testing.T) {
+	assert := testify.New(t)
+	var activated widget.TreeNodeID
+	tree, ids, w := buildTree(t, WithKeyboard(), WithOnActivate(func(id widget.TreeNodeID, _ TreeModel) {
+		activated = id
+	}))
+	defer w.Close()
+
+	test.Tap(displayedNode(t, tree, ids["a"]).self.(fyne.Tappable))
+	focused := w.Canvas().Focused()
+	assert.Equal(tree.focusTarget, focused, "Tapping a node should focus the tree")
+	focused.TypedKey(&fyne.KeyEvent{Name: fyne.KeyDown})
+	assert.Equal(ids["a1"], tree.Current())
+	assert.Equal([]widget.TreeNodeID{ids["a1"]}, tree.SelectedIDs())
+	focused.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.Equal(ids["a1"], activated)
+	focused.TypedRune('b')
+	assert.Equal(ids["b"], tree.Current(), "Typing should move to a matching node")
+	tree.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	assert.Equal(ids["root"], tree.Current())
+	tree.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	assert.False(tree.IsBranchOpen(ids["root"]))
+}
+
+func TestTree_Rename(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, w := buildTree(t, WithRename())
+	defer w.Close()
+
+	assert.ErrorIs(tree.StartRename(ids["b"]), ErrNotRenamable)
+	node := displayedNode(t, tree, ids["a"])
+	doubleTappable, ok := node.self.(fyne.DoubleTappable)
+	assert.True(ok, "Renamable trees should handle double taps")
+	doubleTappable.DoubleTapped(&fyne.PointEvent{})
+	assert.True(tree.isRenaming(ids["a"]))
+	assert.True(node.render.entry.Visible())
+	assert.Equal("a", node.render.entry.Text)
+
+	node.render.entry.SetText("")
+	node.render.entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.True(tree.isRenaming(ids["a"]), "A failed rename should keep editing")
+	assert.True(node.render.renameError.Visible())
+
+	node.render.entry.SetText("renamed")
+	node.render.entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.False(tree.isRenaming(ids["a"]))
+	assert.Equal("renamed", tree.Node(ids["a"]).DisplayString())
+	assert.Equal("renamed", node.render.label.Text)
+
+	plain, plainIDs, plainWindow := buildTree(t)
+	defer plainWindow.Close()
+	assert.ErrorIs(plain.StartRename(plainIDs["a"]), ErrNotRenamable, "Trees without WithRename can't rename")
+}
+
+func TestTree_Checkboxes(t *testing.T) {
+	assert := testify.New(t)
+	changed := map[widget.TreeNodeID]CheckState{}
+	tree, ids, w := buildTree(t, WithCheckboxes(), WithOnCheckChanged(func(id widget.TreeNodeID, _ TreeModel, state CheckState) {
+		changed[id] = state
+	}))
+	defer w.Close()
+
+	node := displayedNode(t, tree, ids["a1"])
+	assert.True(node.render.check.Visible())
+	test.Tap(node.render.check.check)
+	assert.Equal(Checked, tree.CheckState(ids["a1"]))
+	assert.Equal(Indeterminate, changed[ids["a"]])
+	assert.Equal(Indeterminate, displayedNode(t, tree, ids["root"]).render.check.State())
+	assert.Equal([]widget.TreeNodeID{ids["a1"]}, tree.CheckedIDs())
+
+	plain, plainIDs, plainWindow := buildTree(t)
+	defer plainWindow.Close()
+	assert.False(displayedNode(t, plain, plainIDs["a"]).render.check.Visible())
+}
+
+func TestTree_DragAndDrop(t *testing.T) {
+	assert := testify.New(t)
+	var vetoed bool
+	tree, ids, w := buildTree(t, WithDragAndDrop(), WithOnDrop(func(id widget.TreeNodeID, _ TreeModel, target DropTarget) bool {
+		return !vetoed
+	}))
+	defer w.Close()
+
+	dragOnto := func(id, target widget.TreeNodeID) {
+		node := displayedNode(t, tree, id)
+		targetNode := displayedNode(t, tree, target)
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(targetNode.self)
+		pos = pos.Add(fyne.NewPos(1, targetNode.Size().Height/2))
+		node.self.(fyne.Draggable).Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{AbsolutePosition: pos}})
+	}
+	dragOnto(ids["a1"], ids["b"])
+	assert.Equal(DropInto, targetPosition(tree, ids["b"]))
+	displayedNode(t, tree, ids["a1"]).self.(fyne.Draggable).DragEnd()
+	assert.Equal(ids["b"], tree.Parent(ids["a1"]))
+	assert.Equal(DropNone, targetPosition(tree, ids["b"]), "Dropping should clear the drop target")
+
+	vetoed = true
+	dragOnto(ids["a2"], ids["b"])
+	displayedNode(t, tree, ids["a2"]).self.(fyne.Draggable).DragEnd()
+	assert.Equal(ids["a"], tree.Parent(ids["a2"]), "OnDrop should be able to veto a drop")
+}
+
+func targetPosition(tree *Tree, id widget.TreeNodeID) DropPosition {
+	return tree.dropPositionFor(id)
+}
+
+func TestTree_ViewState(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, w := buildTree(t, WithMultiSelect())
+	defer w.Close()
+
+	tree.CloseBranch(ids["a"])
+	tree.selection.SelectIDs(ids["a"], ids["b"])
+	state := tree.SaveViewState()
+	assert.Equal([]widget.TreeNodeID{ids["root"]}, state.Open)
+	assert.Equal([]widget.TreeNodeID{ids["a"], ids["b"]}, state.Selected)
+	assert.Equal(ids["root"], state.ScrollTop)
+
+	restored, _, restoredWindow := buildTree(t, WithMultiSelect())
+	defer restoredWindow.Close()
+	state.Selected = append(state.Selected, "does not exist")
+	restored.RestoreViewState(state)
+	assert.True(restored.IsBranchOpen(ids["root"]))
+	assert.False(restored.IsBranchOpen(ids["a"]))
+	assert.Equal([]widget.TreeNodeID{ids["a"], ids["b"]}, restored.SelectedIDs())
+	assert.Equal(ids["a"], restored.Current())
+}
+
+func TestTree_SetFilter(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, _ := buildTree(t, WithKeyboard())
+	tree.SetFilter(func(id widget.TreeNodeID) bool {
+		return id != ids["a"] && id != ids["b"]
+	})
+	assert.Equal([]widget.TreeNodeID{ids["root"]}, tree.visibleIDs(), "Filtered nodes should be hidden with their descendants")
+	assert.Empty(tree.childIDs(ids["root"]))
+	assert.False(tree.hasChildren(ids["root"]))
+	assert.Len(tree.Children(ids["root"]), 2, "The registry shouldn't be filtered")
+
+	tree.SetFilter(func(id widget.TreeNodeID) bool {
+		return id != ids["a1"]
+	})
+	tree.MoveTo(ids["a"])
+	tree.SelectNext(ids["a"])
+	assert.Equal(ids["a2"], tree.Current(), "Keyboard navigation should skip filtered nodes")
+
+	tree.SetFilter(nil)
+	assert.Len(tree.visibleIDs(), 5)
+}
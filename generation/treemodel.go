@@ -18,13 +18,27 @@ type TreeModel interface {
 	RemoveChildAt(int) TreeModel     // RemoveChildAt removes a child from the child list if one exists at the given location. Returns nil if nothing was removed or if the index was out of bounds.
 }
 
+// ObservableTreeModel may be implemented by a TreeModel to push its own changes to the registry, instead of relying on
+// callers to use TreeModelRegistry.Updated.
+type ObservableTreeModel interface {
+	TreeModel
+	AddChangeListener(listener func()) (remove func()) // AddChangeListener is called by each registry the model is registered with, which calls remove when the model is deregistered. The model should call every listener whenever its display changes.
+}
+
 var _ TreeModel = (*BaseTreeModel)(nil)
+var _ ObservableTreeModel = (*BaseTreeModel)(nil)
 var ErrBadIndex = errors.New("invalid index")
 
 // BaseTreeModel is a helper type that implements TreeModel. Users only need to override DisplayIcon and/or DisplayString for read-only or non-persistent models.
+// Embedding types may call NotifyChanged to refresh their node after a change.
 type BaseTreeModel struct {
-	mux      sync.RWMutex
-	children []TreeModel
+	mux       sync.RWMutex
+	children  []TreeModel
+	listeners []*changeListener
+}
+
+type changeListener struct {
+	listener func()
 }
 
 func (b *BaseTreeModel) DisplayIcon() fyne.Resource {
@@ -58,7 +72,9 @@ func (b *BaseTreeModel) AddChildAt(index int, newModel TreeModel) error {
 		return errors.Wrapf(ErrBadIndex, "index '%d' out of bounds", index)
 	}
 
-	b.children = append(append(b.children[0:index], newModel), b.children[index:]...)
+	b.children = append(b.children, nil)
+	copy(b.children[index+1:], b.children[index:])
+	b.children[index] = newModel
 	return nil
 }
 
@@ -81,10 +97,37 @@ func (b *BaseTreeModel) RemoveChildAt(index int) TreeModel {
 	if childLen == 0 {
 		return nil
 	}
-	if index < 0 || index > childLen {
+	if index < 0 || index >= childLen {
 		return nil
 	}
 	removed := b.children[index]
 	b.children = append(b.children[:index], b.children[index+1:]...)
 	return removed
 }
+
+func (b *BaseTreeModel) AddChangeListener(listener func()) (remove func()) {
+	entry := &changeListener{listener: listener}
+	b.mux.Lock()
+	b.listeners = append(b.listeners, entry)
+	b.mux.Unlock()
+	return func() {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+		for i, l := range b.listeners {
+			if l == entry {
+				b.listeners = append(b.listeners[:i:i], b.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// NotifyChanged tells the registries this model is registered with, if any, that its display has changed.
+func (b *BaseTreeModel) NotifyChanged() {
+	b.mux.RLock()
+	listeners := b.listeners
+	b.mux.RUnlock()
+	for _, l := range listeners {
+		l.listener()
+	}
+}
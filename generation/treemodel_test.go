@@ -65,6 +65,22 @@ func TestBaseTreeModel_AddChildAt(t *testing.T) {
 	}
 }
 
+func TestBaseTreeModel_AddChildAt_Order(t *testing.T) {
+	assert := testify.New(t)
+	base := &BaseTreeModel{}
+	for _, data := range []string{"a", "c", "e"} {
+		assert.NoError(base.AddChild(&ModelData{Data: data}))
+	}
+	assert.NoError(base.AddChildAt(1, &ModelData{Data: "b"}))
+	assert.NoError(base.AddChildAt(3, &ModelData{Data: "d"}))
+
+	var order []string
+	for _, c := range base.Children() {
+		order = append(order, c.DisplayString())
+	}
+	assert.Equal([]string{"a", "b", "c", "d", "e"}, order, "Existing children should be shifted, not overwritten")
+}
+
 func TestBaseTreeModel_AddChildAt_Neg(t *testing.T) {
 	base := &BaseTreeModel{}
 	c := &BaseTreeModel{}
@@ -174,6 +190,13 @@ func TestBaseTreeModel_RemoveChildAt(t *testing.T) {
 			OldLen:  3,
 			NewLen:  3,
 		},
+		{
+			Name:    "Remove at length",
+			Index:   3,
+			Removed: nil,
+			OldLen:  3,
+			NewLen:  3,
+		},
 		{
 			Name:    "Remove past beginning",
 			Index:   -7,
@@ -228,3 +251,26 @@ func TestBaseTreeModel_RemoveChildAt(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseTreeModel_NotifyChanged(t *testing.T) {
+	assert := testify.New(t)
+	base := &BaseTreeModel{}
+	base.NotifyChanged()
+
+	var first, second int
+	removeFirst := base.AddChangeListener(func() {
+		first++
+	})
+	base.AddChangeListener(func() {
+		second++
+	})
+	base.NotifyChanged()
+	assert.Equal(1, first)
+	assert.Equal(1, second)
+
+	removeFirst()
+	removeFirst()
+	base.NotifyChanged()
+	assert.Equal(1, first, "Removed listener should not be called")
+	assert.Equal(2, second, "Other listeners should still be called")
+}
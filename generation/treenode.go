@@ -0,0 +1,752 @@
+package generation
+
+import (
+	"image/color"
+	"strconv"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/drognisep/fynehelpers/layouthelp"
+)
+
+var _ fyne.Widget = (*treeNode)(nil)
+var _ fyne.Tappable = (*treeNode)(nil)
+var _ fyne.SecondaryTappable = (*treeNode)(nil)
+var _ fyne.DoubleTappable = doubleTapEvents{}
+var _ desktop.Hoverable = hoverEvents{}
+var _ desktop.Mouseable = mouseEvents{}
+var _ fyne.Draggable = dragEvents{}
+
+// treeNode displays a node of a Tree. It handles taps itself, and is wrapped by newTreeNode to handle the other events
+// the tree's features need.
+type treeNode struct {
+	widget.BaseWidget
+
+	mux          sync.RWMutex
+	id           widget.TreeNodeID
+	self         fyne.CanvasObject // self is the object added to the tree, which may wrap this node.
+	presentation NodePresentation
+	model        TreeModel
+	render       *treeNodeRenderer
+	tree         *Tree
+	checkState   CheckState
+	selected     bool
+	modifier     desktop.Modifier // modifier is the keyboard modifier held when the last press started, for the following tap.
+	tooltip      *widget.PopUp
+	hovered      bool
+	drop         DropPosition
+	editing      bool
+}
+
+// doubleTapEvents makes a node double tappable. It's only used when the tree handles double taps, since Fyne delays
+// single taps on objects that could be double tapped.
+type doubleTapEvents struct{ node *treeNode }
+
+func (e doubleTapEvents) DoubleTapped(event *fyne.PointEvent) { e.node.doubleTapped(event) }
+
+// hoverEvents makes a node hoverable, for tooltips and hover handlers.
+type hoverEvents struct{ node *treeNode }
+
+func (e hoverEvents) MouseIn(event *desktop.MouseEvent)    { e.node.mouseIn(event) }
+func (e hoverEvents) MouseMoved(event *desktop.MouseEvent) { e.node.mouseMoved(event) }
+func (e hoverEvents) MouseOut()                            { e.node.mouseOut() }
+
+// mouseEvents makes a node receive mouse buttons, for multi-select modifiers and mouse handlers.
+type mouseEvents struct{ node *treeNode }
+
+func (e mouseEvents) MouseDown(event *desktop.MouseEvent) { e.node.mouseDown(event) }
+func (e mouseEvents) MouseUp(event *desktop.MouseEvent)   { e.node.mouseUp(event) }
+
+// dragEvents makes a node draggable. It's only used for drag and drop, since draggable nodes stop touch screens from
+// scrolling the tree.
+type dragEvents struct{ node *treeNode }
+
+func (e dragEvents) Dragged(event *fyne.DragEvent) { e.node.dragged(event) }
+func (e dragEvents) DragEnd()                      { e.node.dragEnd() }
+
+// The types below add events to treeNode, and are named for the events they add: D for double taps, H for hover, M for
+// mouse buttons and G for drags. Fyne finds event handlers by their interfaces, so each combination needs its own type.
+type (
+	treeNodeD struct {
+		*treeNode
+		doubleTapEvents
+	}
+	treeNodeH struct {
+		*treeNode
+		hoverEvents
+	}
+	treeNodeM struct {
+		*treeNode
+		mouseEvents
+	}
+	treeNodeG struct {
+		*treeNode
+		dragEvents
+	}
+	treeNodeDH struct {
+		*treeNode
+		doubleTapEvents
+		hoverEvents
+	}
+	treeNodeDM struct {
+		*treeNode
+		doubleTapEvents
+		mouseEvents
+	}
+	treeNodeDG struct {
+		*treeNode
+		doubleTapEvents
+		dragEvents
+	}
+	treeNodeHM struct {
+		*treeNode
+		hoverEvents
+		mouseEvents
+	}
+	treeNodeHG struct {
+		*treeNode
+		hoverEvents
+		dragEvents
+	}
+	treeNodeMG struct {
+		*treeNode
+		mouseEvents
+		dragEvents
+	}
+	treeNodeDHM struct {
+		*treeNode
+		doubleTapEvents
+		hoverEvents
+		mouseEvents
+	}
+	treeNodeDHG struct {
+		*treeNode
+		doubleTapEvents
+		hoverEvents
+		dragEvents
+	}
+	treeNodeDMG struct {
+		*treeNode
+		doubleTapEvents
+		mouseEvents
+		dragEvents
+	}
+	treeNodeHMG struct {
+		*treeNode
+		hoverEvents
+		mouseEvents
+		dragEvents
+	}
+	treeNodeDHMG struct {
+		*treeNode
+		doubleTapEvents
+		hoverEvents
+		mouseEvents
+		dragEvents
+	}
+)
+
+// newTreeNode creates a node that only handles the events the tree's features and handlers need, so the handlers must
+// be set before the tree is shown.
+func newTreeNode(tree *Tree) fyne.CanvasObject {
+	node := &treeNode{
+		tree: tree,
+	}
+	d, h, m, g := doubleTapEvents{node}, hoverEvents{node}, mouseEvents{node}, dragEvents{node}
+	var events string
+	if tree.editable || tree.OnDoubleTapped != nil {
+		events += "D"
+	}
+	if tree.tooltips || tree.OnMouseIn != nil || tree.OnMouseMoved != nil || tree.OnMouseOut != nil {
+		events += "H"
+	}
+	if tree.multiSelect || tree.OnMouseDown != nil || tree.OnMouseUp != nil {
+		events += "M"
+	}
+	if tree.draggable {
+		events += "G"
+	}
+	switch events {
+	case "":
+		node.self = node
+	case "D":
+		node.self = &treeNodeD{node, d}
+	case "H":
+		node.self = &treeNodeH{node, h}
+	case "M":
+		node.self = &treeNodeM{node, m}
+	case "G":
+		node.self = &treeNodeG{node, g}
+	case "DH":
+		node.self = &treeNodeDH{node, d, h}
+	case "DM":
+		node.self = &treeNodeDM{node, d, m}
+	case "DG":
+		node.self = &treeNodeDG{node, d, g}
+	case "HM":
+		node.self = &treeNodeHM{node, h, m}
+	case "HG":
+		node.self = &treeNodeHG{node, h, g}
+	case "MG":
+		node.self = &treeNodeMG{node, m, g}
+	case "DHM":
+		node.self = &treeNodeDHM{node, d, h, m}
+	case "DHG":
+		node.self = &treeNodeDHG{node, d, h, g}
+	case "DMG":
+		node.self = &treeNodeDMG{node, d, m, g}
+	case "HMG":
+		node.self = &treeNodeHMG{node, h, m, g}
+	case "DHMG":
+		node.self = &treeNodeDHMG{node, d, h, m, g}
+	}
+	node.ExtendBaseWidget(node.self.(fyne.Widget))
+	return node.self
+}
+
+func (t *treeNode) base() *treeNode {
+	return t
+}
+
+// asTreeNode unwraps a node created by newTreeNode.
+func asTreeNode(obj fyne.CanvasObject) *treeNode {
+	if node, ok := obj.(interface{ base() *treeNode }); ok {
+		return node.base()
+	}
+	return nil
+}
+
+func (t *treeNode) CreateRenderer() fyne.WidgetRenderer {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.render = newTreeNodeRenderer(t)
+	t.render.present(t.id, t.model, t.presentation)
+	t.render.check.SetState(t.checkState)
+	t.render.setSelected(t.selected)
+	t.render.setDrop(t.drop)
+	t.render.setHovered(t.hovered && t.tree.HighlightHover)
+	t.render.setEditing(t.editing)
+	if t.editing {
+		t.render.entry.Edit(t.presentation.Text)
+	}
+	return t.render
+}
+
+func (t *treeNode) update(id widget.TreeNodeID, model TreeModel) {
+	checkState := t.tree.CheckState(id)
+	selected := t.tree.multiSelect && t.tree.IsSelected(id)
+	drop := t.tree.dropPositionFor(id)
+	editing := t.tree.isRenaming(id)
+	t.mux.Lock()
+	t.id = id
+	t.model = model
+	t.presentation = PresentationOf(model)
+	t.checkState = checkState
+	t.selected = selected
+	t.drop = drop
+	startEdit := editing && !t.editing
+	t.editing = editing
+	render := t.render
+	if render != nil {
+		render.present(id, model, t.presentation)
+		render.check.SetState(checkState)
+		render.setSelected(selected)
+		render.setDrop(drop)
+		render.setEditing(editing)
+	}
+	text := t.presentation.Text
+	t.mux.Unlock()
+	if render != nil {
+		switch {
+		case startEdit:
+			render.entry.Edit(text)
+		case !editing && render.entry.Editing():
+			// The node was rebound to another ID while editing, so the edit is abandoned.
+			render.entry.Cancel()
+		}
+	}
+	t.Refresh()
+}
+
+func (t *treeNode) nodeID() widget.TreeNodeID {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	return t.id
+}
+
+func (t *treeNode) checkChanged(checked bool) {
+	t.tree.SetChecked(t.nodeID(), checked)
+}
+
+func (t *treeNode) commitRename(name string) error {
+	err := t.tree.commitRename(name)
+	t.mux.RLock()
+	if t.render != nil {
+		t.render.setRenameError(err)
+	}
+	t.mux.RUnlock()
+	t.Refresh()
+	return err
+}
+
+func (t *treeNode) cancelRename() {
+	t.tree.stopRename()
+}
+
+// renameChanged clears the last rename error, and lays out the node again to fit the new text.
+func (t *treeNode) renameChanged(string) {
+	t.mux.RLock()
+	if t.render != nil {
+		t.render.setRenameError(nil)
+	}
+	t.mux.RUnlock()
+	t.Refresh()
+}
+
+// Tapped selects the node, since taps no longer reach the widget.Tree row.
+func (t *treeNode) Tapped(event *fyne.PointEvent) {
+	t.mux.Lock()
+	id := t.id
+	modifier := t.modifier
+	t.modifier = 0
+	t.mux.Unlock()
+	if t.tree.multiSelect {
+		t.tree.selectWithModifier(id, modifier)
+	} else {
+		t.tree.Select(id)
+	}
+	t.tree.setCurrent(id)
+	if t.tree.keyboard || t.tree.editable {
+		t.tree.focus()
+	}
+	if t.tree.OnTapped != nil {
+		t.tree.OnTapped(id, t.tree.Node(id), event)
+	}
+}
+
+func (t *treeNode) doubleTapped(event *fyne.PointEvent) {
+	id := t.nodeID()
+	if t.tree.editable && t.tree.CanRename(id) {
+		t.tree.StartRename(id)
+	}
+	if t.tree.OnDoubleTapped != nil {
+		t.tree.OnDoubleTapped(id, t.tree.Node(id), event)
+	}
+}
+
+func (t *treeNode) TappedSecondary(event *fyne.PointEvent) {
+	id := t.nodeID()
+	if t.tree.OnTappedSecondary != nil {
+		t.tree.OnTappedSecondary(id, t.tree.Node(id), event)
+	}
+	if t.tree.contextMenu {
+		t.tree.showContextMenu(id, event)
+	}
+}
+
+// mouseDown records the keyboard modifier for the tap that follows, since taps don't report it.
+func (t *treeNode) mouseDown(event *desktop.MouseEvent) {
+	t.mux.Lock()
+	id := t.id
+	t.modifier = event.Modifier
+	t.mux.Unlock()
+	if t.tree.OnMouseDown != nil {
+		t.tree.OnMouseDown(id, t.tree.Node(id), event)
+	}
+}
+
+func (t *treeNode) mouseUp(event *desktop.MouseEvent) {
+	id := t.nodeID()
+	if t.tree.OnMouseUp != nil {
+		t.tree.OnMouseUp(id, t.tree.Node(id), event)
+	}
+}
+
+// mouseIn highlights the node, and shows the model's description if the tree has tooltips.
+func (t *treeNode) mouseIn(event *desktop.MouseEvent) {
+	t.mux.Lock()
+	id := t.id
+	t.hovered = true
+	if t.render != nil {
+		t.render.setHovered(t.tree.HighlightHover)
+	}
+	if t.tree.tooltips {
+		t.showTooltip(event.AbsolutePosition)
+	}
+	t.mux.Unlock()
+	t.Refresh()
+	if t.tree.OnMouseIn != nil {
+		t.tree.OnMouseIn(id, t.tree.Node(id), event)
+	}
+}
+
+func (t *treeNode) mouseMoved(event *desktop.MouseEvent) {
+	id := t.nodeID()
+	if t.tree.OnMouseMoved != nil {
+		t.tree.OnMouseMoved(id, t.tree.Node(id), event)
+	}
+}
+
+func (t *treeNode) mouseOut() {
+	t.mux.Lock()
+	id := t.id
+	t.hovered = false
+	if t.render != nil {
+		t.render.setHovered(false)
+	}
+	if t.tooltip != nil {
+		t.tooltip.Hide()
+		t.tooltip = nil
+	}
+	t.mux.Unlock()
+	t.Refresh()
+	if t.tree.OnMouseOut != nil {
+		t.tree.OnMouseOut(id, t.tree.Node(id))
+	}
+}
+
+// showTooltip shows the model's description at pos, if it has one. This must be called while the node is locked.
+func (t *treeNode) showTooltip(pos fyne.Position) {
+	description := t.presentation.Description
+	if description == "" {
+		return
+	}
+	c := fyne.CurrentApp().Driver().CanvasForObject(t.self)
+	if c == nil {
+		return
+	}
+	t.tooltip = widget.NewPopUp(widget.NewLabel(description), c)
+	t.tooltip.ShowAtPosition(pos.Add(fyne.NewPos(theme.Padding(), theme.IconInlineSize())))
+}
+
+// dragged moves the tree's drop target to follow the pointer.
+func (t *treeNode) dragged(event *fyne.DragEvent) {
+	t.tree.dragged(t.nodeID(), event.AbsolutePosition)
+}
+
+// dragEnd drops this node on the tree's drop target.
+func (t *treeNode) dragEnd() {
+	t.tree.dragEnd(t.nodeID())
+}
+
+var _ fyne.WidgetRenderer = (*treeNodeRenderer)(nil)
+
+type treeNodeRenderer struct {
+	node          *treeNode
+	background    *canvas.Rectangle // background highlights the node's state, and is drawn behind the content.
+	hover         *canvas.Rectangle // hover highlights the node under the pointer, since the node hides hover events from the tree's row.
+	dropIndicator *canvas.Rectangle // dropIndicator shows where a dragged node will be dropped, and is drawn over the content.
+	drop          DropPosition
+	check         *TriStateCheck
+	icon          *widget.Icon
+	label         *widget.Label
+	text          *canvas.Text    // text replaces label when the model has a color.
+	highlighted   *fyne.Container // highlighted replaces label and text when the tree highlights part of the text.
+	custom        NodeContent     // custom replaces the icon, label, subtitle and badge when the tree has a NodeContentFactory.
+	customBox     *fyne.Container
+	entry         *RenameEntry // entry replaces label and text while renaming.
+	renameError   *canvas.Text
+	editing       bool
+	subtitle      *canvas.Text
+	badge         *fyne.Container
+	badgeText     *canvas.Text
+	layout        fyne.Layout
+	content       []fyne.CanvasObject // content holds the objects arranged by layout.
+	objects       []fyne.CanvasObject
+}
+
+func newTreeNodeRenderer(node *treeNode) *treeNodeRenderer {
+	render := &treeNodeRenderer{
+		node:       node,
+		background: canvas.NewRectangle(theme.SelectionColor()),
+		hover:      canvas.NewRectangle(theme.HoverColor()),
+		icon:       &widget.Icon{},
+		label: &widget.Label{
+			Alignment: fyne.TextAlignLeading,
+			TextStyle: fyne.TextStyle{},
+		},
+		text:        canvas.NewText("", theme.ForegroundColor()),
+		highlighted: container.New(textRunLayout{}),
+		subtitle:    canvas.NewText("", theme.DisabledColor()),
+		badgeText:   canvas.NewText("", theme.BackgroundColor()),
+		layout:      layout.NewHBoxLayout(),
+	}
+	render.check = NewTriStateCheck(node.checkChanged)
+	if !node.tree.checkable {
+		render.check.Hide()
+	}
+	render.subtitle.TextSize = theme.CaptionTextSize()
+	render.badgeText.TextSize = theme.CaptionTextSize()
+	render.badgeText.Alignment = fyne.TextAlignCenter
+	render.badge = container.NewMax(canvas.NewRectangle(theme.PrimaryColor()), container.NewPadded(render.badgeText))
+	render.background.Hide()
+	render.hover.Hide()
+	render.text.Hide()
+	render.highlighted.Hide()
+	render.subtitle.Hide()
+	render.badge.Hide()
+	render.entry = NewRenameEntry()
+	render.entry.OnCommit = node.commitRename
+	render.entry.OnCancel = node.cancelRename
+	render.entry.OnChanged = node.renameChanged
+	render.entry.Hide()
+	render.renameError = canvas.NewText("", theme.ErrorColor())
+	render.renameError.TextSize = theme.CaptionTextSize()
+	render.renameError.Hide()
+	if node.tree.nodeContent != nil {
+		// Custom content fills the width left by the check, and is swapped for the entry while renaming.
+		render.custom = node.tree.nodeContent()
+		render.customBox = NewNodeContentContainer(render.custom)
+		editor := container.NewHBox(render.entry, render.renameError)
+		render.layout = layout.NewMaxLayout()
+		render.content = []fyne.CanvasObject{
+			container.NewBorder(nil, nil, render.check, nil, container.NewMax(render.customBox, editor)),
+		}
+	} else {
+		render.content = []fyne.CanvasObject{render.check, render.icon, render.label, render.text, render.highlighted, render.entry, render.renameError, render.subtitle, layout.NewSpacer(), render.badge}
+	}
+	render.objects = append([]fyne.CanvasObject{render.background, render.hover}, render.content...)
+	render.dropIndicator = canvas.NewRectangle(theme.PrimaryColor())
+	render.dropIndicator.Hide()
+	render.objects = append(render.objects, render.dropIndicator)
+	return render
+}
+
+// present applies the model's presentation to the rendered objects, or updates the custom content with the model.
+func (r *treeNodeRenderer) present(id widget.TreeNodeID, model TreeModel, p NodePresentation) {
+	if r.custom != nil {
+		if model != nil {
+			r.custom.Update(id, model)
+		}
+		return
+	}
+	r.icon.SetResource(p.Icon)
+	r.label.TextStyle = p.TextStyle
+	r.label.SetText(p.Text)
+	r.text.Text = p.Text
+	r.text.TextStyle = p.TextStyle
+	var ranges [][2]int
+	if r.node.tree.highlight != nil {
+		ranges = r.node.tree.highlight(p.Text)
+	}
+	switch {
+	case len(ranges) > 0:
+		r.highlighted.Objects = highlightedText(p, ranges)
+		r.highlighted.Refresh()
+		r.highlighted.Show()
+		r.text.Hide()
+		r.label.Hide()
+	case p.TextColor != nil:
+		r.text.Color = p.TextColor
+		r.text.Show()
+		r.label.Hide()
+		r.highlighted.Hide()
+	default:
+		r.text.Hide()
+		r.label.Show()
+		r.highlighted.Hide()
+	}
+	r.subtitle.Text = p.Subtitle
+	if p.Subtitle != "" {
+		r.subtitle.Show()
+	} else {
+		r.subtitle.Hide()
+	}
+	if p.Badge != 0 {
+		r.badgeText.Text = strconv.Itoa(p.Badge)
+		r.badge.Show()
+	} else {
+		r.badge.Hide()
+	}
+}
+
+// highlightedText splits the presented text into pieces, with the ranges given in bold and the primary color. The
+// other pieces keep the model's style and color.
+func highlightedText(p NodePresentation, ranges [][2]int) []fyne.CanvasObject {
+	runes := []rune(p.Text)
+	textColor := p.TextColor
+	if textColor == nil {
+		textColor = theme.ForegroundColor()
+	}
+	var pieces []fyne.CanvasObject
+	add := func(s string, highlight bool) {
+		if s == "" {
+			return
+		}
+		piece := canvas.NewText(s, textColor)
+		piece.TextStyle = p.TextStyle
+		if highlight {
+			piece.Color = theme.PrimaryColor()
+			piece.TextStyle.Bold = true
+		}
+		pieces = append(pieces, piece)
+	}
+	start := 0
+	for _, match := range ranges {
+		add(string(runes[start:match[0]]), false)
+		add(string(runes[match[0]:match[1]]), true)
+		start = match[1]
+	}
+	add(string(runes[start:]), false)
+	return pieces
+}
+
+// textRunLayout places objects side by side at their minimum width without padding, so pieces of text read as one.
+type textRunLayout struct{}
+
+func (textRunLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	x := float32(0)
+	for _, o := range objects {
+		width := o.MinSize().Width
+		o.Resize(fyne.NewSize(width, size.Height))
+		o.Move(fyne.NewPos(x, 0))
+		x += width
+	}
+}
+
+func (textRunLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	var min fyne.Size
+	for _, o := range objects {
+		size := o.MinSize()
+		min.Width += size.Width
+		min.Height = fyne.Max(min.Height, size.Height)
+	}
+	return min
+}
+
+func (r *treeNodeRenderer) setSelected(selected bool) {
+	r.background.FillColor = theme.SelectionColor()
+	if selected {
+		r.background.Show()
+	} else {
+		r.background.Hide()
+	}
+}
+
+func (r *treeNodeRenderer) setHovered(hovered bool) {
+	r.hover.FillColor = theme.HoverColor()
+	if hovered {
+		r.hover.Show()
+	} else {
+		r.hover.Hide()
+	}
+}
+
+// setEditing swaps the label, or the custom content, for the rename entry. This must be called after present.
+func (r *treeNodeRenderer) setEditing(editing bool) {
+	r.editing = editing
+	if editing {
+		if r.custom != nil {
+			r.customBox.Hide()
+		}
+		r.label.Hide()
+		r.text.Hide()
+		r.highlighted.Hide()
+		r.entry.Show()
+	} else {
+		if r.custom != nil {
+			r.customBox.Show()
+		}
+		r.entry.Hide()
+		r.setRenameError(nil)
+	}
+}
+
+// setRenameError shows the error returned by the model beside the entry.
+func (r *treeNodeRenderer) setRenameError(err error) {
+	if err == nil || !r.editing {
+		r.renameError.Text = ""
+		r.renameError.Hide()
+		return
+	}
+	r.renameError.Text = err.Error()
+	r.renameError.Color = theme.ErrorColor()
+	r.renameError.Show()
+}
+
+// setDrop shows a line above or below the node for DropBefore or DropAfter, and an outline for DropInto.
+func (r *treeNodeRenderer) setDrop(drop DropPosition) {
+	r.drop = drop
+	if drop == DropNone {
+		r.dropIndicator.Hide()
+		return
+	}
+	r.dropIndicator.StrokeColor = theme.PrimaryColor()
+	if drop == DropInto {
+		r.dropIndicator.FillColor = color.Transparent
+		r.dropIndicator.StrokeWidth = 2
+	} else {
+		r.dropIndicator.FillColor = theme.PrimaryColor()
+		r.dropIndicator.StrokeWidth = 0
+	}
+	r.dropIndicator.Show()
+}
+
+func (r *treeNodeRenderer) Destroy() {
+	r.node = nil
+	r.background = nil
+	r.hover = nil
+	r.dropIndicator = nil
+	r.check = nil
+	r.icon = nil
+	r.label = nil
+	r.text = nil
+	r.highlighted = nil
+	r.custom = nil
+	r.customBox = nil
+	r.entry = nil
+	r.renameError = nil
+	r.subtitle = nil
+	r.badge = nil
+	r.badgeText = nil
+	r.layout = nil
+	r.content = nil
+	r.objects = nil
+}
+
+func (r *treeNodeRenderer) Layout(parent fyne.Size) {
+	r.background.Resize(parent)
+	r.background.Move(fyne.NewPos(0, 0))
+	r.hover.Resize(parent)
+	r.hover.Move(fyne.NewPos(0, 0))
+	r.layout.Layout(r.content, parent)
+	switch r.drop {
+	case DropBefore:
+		r.dropIndicator.Resize(fyne.NewSize(parent.Width, 2))
+		r.dropIndicator.Move(fyne.NewPos(0, 0))
+	case DropAfter:
+		r.dropIndicator.Resize(fyne.NewSize(parent.Width, 2))
+		r.dropIndicator.Move(fyne.NewPos(0, parent.Height-2))
+	default:
+		r.dropIndicator.Resize(parent)
+		r.dropIndicator.Move(fyne.NewPos(0, 0))
+	}
+}
+
+func (r *treeNodeRenderer) MinSize() fyne.Size {
+	var sizes []fyne.Size
+	for _, obj := range r.content {
+		if obj.Visible() {
+			sizes = append(sizes, obj.MinSize())
+		}
+	}
+	return layouthelp.AccumulateWidth(sizes...)
+}
+
+func (r *treeNodeRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *treeNodeRenderer) Refresh() {
+	// Presentation changes may change the width of objects, so they need to be laid out again.
+	r.Layout(r.node.Size())
+	for _, obj := range r.objects {
+		obj.Refresh()
+	}
+}
@@ -0,0 +1,64 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+type describedModel struct {
+	ModelData
+}
+
+func (m *describedModel) DisplayDescription() string {
+	return "Description of " + m.Data
+}
+
+func TestTreeNode_Hover(t *testing.T) {
+	assert := testify.New(t)
+	var hovered []widget.TreeNodeID
+	tree, ids, w := buildTree(t, WithTooltips(), WithOnHover(func(id widget.TreeNodeID, _ TreeModel, _ *desktop.MouseEvent) {
+		hovered = append(hovered, id)
+	}, nil, nil))
+	defer w.Close()
+	described, err := tree.AddChild(ids["root"], &describedModel{ModelData{Data: "c"}})
+	assert.NoError(err)
+	tree.Refresh()
+
+	node := displayedNode(t, tree, ids["a"])
+	hoverable := node.self.(desktop.Hoverable)
+	hoverable.MouseIn(&desktop.MouseEvent{})
+	assert.True(node.render.hover.Visible())
+	assert.Nil(node.tooltip, "Models without a description shouldn't show a tooltip")
+	hoverable.MouseOut()
+	assert.False(node.render.hover.Visible())
+
+	describedNode := displayedNode(t, tree, described)
+	describedNode.self.(desktop.Hoverable).MouseIn(&desktop.MouseEvent{})
+	assert.NotNil(describedNode.tooltip)
+	describedNode.self.(desktop.Hoverable).MouseOut()
+	assert.Nil(describedNode.tooltip)
+	assert.Equal([]widget.TreeNodeID{ids["a"], described}, hovered)
+
+	tree.HighlightHover = false
+	hoverable.MouseIn(&desktop.MouseEvent{})
+	assert.False(node.render.hover.Visible(), "HighlightHover should be able to disable the highlight")
+}
+
+func TestTreeNode_Presentation(t *testing.T) {
+	assert := testify.New(t)
+	tree, ids, w := buildTree(t)
+	defer w.Close()
+	id, err := tree.AddChild(ids["b"], &presentedModel{ModelData: ModelData{Data: "shown"}})
+	assert.NoError(err)
+	tree.OpenBranch(ids["b"])
+
+	node := displayedNode(t, tree, id)
+	assert.Equal("shown", node.render.text.Text)
+	assert.True(node.render.subtitle.Visible())
+	assert.True(node.render.badge.Visible())
+	assert.Equal(fyne.TextStyle{Bold: true}, node.render.text.TextStyle)
+}
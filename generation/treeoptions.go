@@ -0,0 +1,180 @@
+package generation
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TreeOption configures a Tree as it's created by NewTree.
+type TreeOption func(t *Tree)
+
+// WithRoots adds roots to the tree's registry once every other option has been applied.
+func WithRoots(roots ...TreeModel) TreeOption {
+	return func(t *Tree) {
+		t.roots = append(t.roots, roots...)
+	}
+}
+
+// WithIDStrategy sets the strategy the tree's registry uses to create node IDs, see TreeModelRegistry.SetIDStrategy.
+func WithIDStrategy(strategy IDStrategy) TreeOption {
+	return func(t *Tree) {
+		t.idStrategy = strategy
+	}
+}
+
+// WithRegistry displays the nodes in an existing registry, instead of creating an empty one.
+func WithRegistry(registry *TreeModelRegistry) TreeOption {
+	return func(t *Tree) {
+		t.TreeModelRegistry = registry
+	}
+}
+
+// WithExtendingWidget is used by widgets that embed a Tree, like generated trees, so the tree renders and positions
+// itself as w. It's passed to ExtendBaseWidget, and must be the widget that's added to the window.
+func WithExtendingWidget(w fyne.Widget) TreeOption {
+	return func(t *Tree) {
+		t.self = w
+	}
+}
+
+// WithErrorHandler handles errors the tree can't return, like failing to add a root or drop a node. They're logged if
+// handler is nil.
+func WithErrorHandler(handler func(err error)) TreeOption {
+	return func(t *Tree) {
+		t.errorHandler = handler
+	}
+}
+
+// WithNodeContent displays models with content created by factory, instead of an icon and label.
+func WithNodeContent(factory NodeContentFactory) TreeOption {
+	return func(t *Tree) {
+		t.nodeContent = factory
+	}
+}
+
+// WithCheckboxes shows a tri-state checkbox on every node, see CheckSet.
+func WithCheckboxes() TreeOption {
+	return func(t *Tree) {
+		t.checkable = true
+	}
+}
+
+// WithMultiSelect selects nodes with desktop semantics, where control or super toggles a node and shift selects a
+// range, see SelectionSet.
+func WithMultiSelect() TreeOption {
+	return func(t *Tree) {
+		t.multiSelect = true
+	}
+}
+
+// WithDragAndDrop lets nodes be dragged before, after or into other nodes.
+func WithDragAndDrop() TreeOption {
+	return func(t *Tree) {
+		t.draggable = true
+	}
+}
+
+// WithRename lets nodes with a RenamableTreeModel be renamed by double tapping them or pressing F2.
+func WithRename() TreeOption {
+	return func(t *Tree) {
+		t.editable = true
+	}
+}
+
+// WithKeyboard lets the tree be focused and navigated with the keyboard, see Tree.KeyActions.
+func WithKeyboard() TreeOption {
+	return func(t *Tree) {
+		t.keyboard = true
+	}
+}
+
+// WithTooltips shows the description of models implementing DescribedTreeModel when the pointer is over their node.
+func WithTooltips() TreeOption {
+	return func(t *Tree) {
+		t.tooltips = true
+	}
+}
+
+// WithContextMenu shows a menu when a node is secondary tapped, with the items from menuFor followed by those from
+// models implementing ContextMenuTreeModel. menuFor may be nil to only show items from models.
+func WithContextMenu(menuFor func(id widget.TreeNodeID, model TreeModel) *fyne.Menu) TreeOption {
+	return func(t *Tree) {
+		t.contextMenu = true
+		t.MenuFor = menuFor
+	}
+}
+
+// WithOnTapped sets Tree.OnTapped.
+func WithOnTapped(handler func(id widget.TreeNodeID, model TreeModel, event *fyne.PointEvent)) TreeOption {
+	return func(t *Tree) {
+		t.OnTapped = handler
+	}
+}
+
+// WithOnDoubleTapped sets Tree.OnDoubleTapped.
+func WithOnDoubleTapped(handler func(id widget.TreeNodeID, model TreeModel, event *fyne.PointEvent)) TreeOption {
+	return func(t *Tree) {
+		t.OnDoubleTapped = handler
+	}
+}
+
+// WithOnTappedSecondary sets Tree.OnTappedSecondary.
+func WithOnTappedSecondary(handler func(id widget.TreeNodeID, model TreeModel, event *fyne.PointEvent)) TreeOption {
+	return func(t *Tree) {
+		t.OnTappedSecondary = handler
+	}
+}
+
+// WithOnHover sets Tree.OnMouseIn, Tree.OnMouseMoved and Tree.OnMouseOut. Any of them may be nil.
+func WithOnHover(in, moved func(id widget.TreeNodeID, model TreeModel, event *desktop.MouseEvent), out func(id widget.TreeNodeID, model TreeModel)) TreeOption {
+	return func(t *Tree) {
+		t.OnMouseIn = in
+		t.OnMouseMoved = moved
+		t.OnMouseOut = out
+	}
+}
+
+// WithOnMouse sets Tree.OnMouseDown and Tree.OnMouseUp. Either of them may be nil.
+func WithOnMouse(down, up func(id widget.TreeNodeID, model TreeModel, event *desktop.MouseEvent)) TreeOption {
+	return func(t *Tree) {
+		t.OnMouseDown = down
+		t.OnMouseUp = up
+	}
+}
+
+// WithOnSelected sets Tree.OnSelected and Tree.OnUnselected. Either of them may be nil.
+func WithOnSelected(selected, unselected func(id widget.TreeNodeID)) TreeOption {
+	return func(t *Tree) {
+		t.OnSelected = selected
+		t.OnUnselected = unselected
+	}
+}
+
+// WithOnSelectionChanged sets Tree.OnSelectionChanged.
+func WithOnSelectionChanged(handler func(selected []widget.TreeNodeID)) TreeOption {
+	return func(t *Tree) {
+		t.OnSelectionChanged = handler
+	}
+}
+
+// WithOnCheckChanged sets Tree.OnCheckChanged.
+func WithOnCheckChanged(handler func(id widget.TreeNodeID, model TreeModel, state CheckState)) TreeOption {
+	return func(t *Tree) {
+		t.OnCheckChanged = handler
+	}
+}
+
+// WithOnDrop sets Tree.OnDrop.
+func WithOnDrop(handler func(id widget.TreeNodeID, model TreeModel, target DropTarget) bool) TreeOption {
+	return func(t *Tree) {
+		t.OnDrop = handler
+	}
+}
+
+// WithOnActivate sets Tree.OnActivate.
+func WithOnActivate(handler func(id widget.TreeNodeID, model TreeModel)) TreeOption {
+	return func(t *Tree) {
+		t.OnActivate = handler
+	}
+}
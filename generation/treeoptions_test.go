@@ -0,0 +1,186 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestNewTree_Options(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	plain := NewTree()
+	assert.Empty(plain.KeyActions, "Key actions should only be added for enabled features")
+	assert.Nil(plain.TypeAhead)
+	assert.True(plain.HighlightHover)
+
+	menu := fyne.NewMenu("Node")
+	tree := NewTree(
+		WithRoots(&ModelData{Data: "first"}, &ModelData{Data: "second"}),
+		WithIDStrategy(PathStrategy),
+		WithCheckboxes(),
+		WithMultiSelect(),
+		WithDragAndDrop(),
+		WithRename(),
+		WithKeyboard(),
+		WithTooltips(),
+		WithContextMenu(func(widget.TreeNodeID, TreeModel) *fyne.Menu {
+			return menu
+		}),
+	)
+	assert.True(tree.checkable)
+	assert.True(tree.multiSelect)
+	assert.True(tree.draggable)
+	assert.True(tree.editable)
+	assert.True(tree.keyboard)
+	assert.True(tree.tooltips)
+	assert.True(tree.contextMenu)
+	assert.Equal(menu, tree.MenuFor("", nil))
+	assert.Contains(tree.KeyActions, fyne.KeyDown)
+	assert.Contains(tree.KeyActions, fyne.KeyF2)
+	assert.NotNil(tree.TypeAhead)
+	assert.Equal([]widget.TreeNodeID{"first", "second"}, tree.Children(ModelRoot), "Roots should be added with the ID strategy")
+
+	renameOnly := NewTree(WithRename())
+	assert.Equal([]fyne.KeyName{fyne.KeyF2}, keyNames(renameOnly.KeyActions))
+}
+
+func TestNewTree_HandlerOptions(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	var called []string
+	record := func(name string) {
+		called = append(called, name)
+	}
+	tree := NewTree(
+		WithOnTapped(func(widget.TreeNodeID, TreeModel, *fyne.PointEvent) { record("tapped") }),
+		WithOnDoubleTapped(func(widget.TreeNodeID, TreeModel, *fyne.PointEvent) { record("double") }),
+		WithOnTappedSecondary(func(widget.TreeNodeID, TreeModel, *fyne.PointEvent) { record("secondary") }),
+		WithOnHover(nil, nil, func(widget.TreeNodeID, TreeModel) { record("out") }),
+		WithOnMouse(nil, nil),
+		WithOnSelected(func(widget.TreeNodeID) { record("selected") }, nil),
+		WithOnSelectionChanged(func([]widget.TreeNodeID) { record("selection") }),
+		WithOnCheckChanged(func(widget.TreeNodeID, TreeModel, CheckState) { record("check") }),
+		WithOnDrop(func(widget.TreeNodeID, TreeModel, DropTarget) bool { record("drop"); return true }),
+		WithOnActivate(func(widget.TreeNodeID, TreeModel) { record("activate") }),
+	)
+	tree.OnTapped("", nil, nil)
+	tree.OnDoubleTapped("", nil, nil)
+	tree.OnTappedSecondary("", nil, nil)
+	assert.Nil(tree.OnMouseIn)
+	tree.OnMouseOut("", nil)
+	assert.Nil(tree.OnMouseDown)
+	tree.OnSelected("")
+	assert.Nil(tree.OnUnselected)
+	tree.OnSelectionChanged(nil)
+	tree.OnCheckChanged("", nil, Checked)
+	tree.OnDrop("", nil, DropTarget{})
+	tree.OnActivate("", nil)
+	assert.Equal([]string{"tapped", "double", "secondary", "out", "selected", "selection", "check", "drop", "activate"}, called)
+}
+
+func TestNewTree_WithRegistry(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	registry := NewTreeModelRegistry()
+	id, err := registry.AddChild(ModelRoot, &ModelData{Data: "existing"})
+	assert.NoError(err)
+	tree := NewTree(WithRegistry(registry), WithIDStrategy(PathStrategy), WithRoots(&ModelData{Data: "added"}))
+	assert.Equal(registry, tree.TreeModelRegistry)
+	assert.Equal([]widget.TreeNodeID{id, "added"}, tree.Children(ModelRoot), "Options should apply to the given registry")
+}
+
+func TestTree_Close(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	registry := NewTreeModelRegistry()
+	listeners := len(registry.listeners)
+	tree := NewTree(WithRegistry(registry))
+	tree.Close()
+	assert.Len(registry.listeners, listeners, "A closed tree shouldn't be kept by a shared registry")
+}
+
+// extendedTree embeds a Tree, like a generated tree.
+type extendedTree struct {
+	*Tree
+}
+
+func TestNewTree_WithExtendingWidget(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	extended := &extendedTree{}
+	extended.Tree = NewTree(
+		WithExtendingWidget(extended),
+		WithIDStrategy(PathStrategy),
+		WithRoots(&ModelData{Data: "root"}),
+		WithContextMenu(func(widget.TreeNodeID, TreeModel) *fyne.Menu {
+			return fyne.NewMenu("", fyne.NewMenuItem("Item", func() {}))
+		}),
+	)
+	w := test.NewWindow(extended)
+	defer w.Close()
+	w.Resize(fyne.NewSize(300, 400))
+
+	_, err := extended.AddChild("root", &ModelData{Data: "child"})
+	assert.NoError(err)
+	extended.OpenBranch("root")
+	node := displayedNode(t, extended.Tree, "root/child")
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(node.self)
+	assert.Greater(pos.Y, float32(0), "Nodes should be displayed in the extending widget")
+	node.self.(fyne.SecondaryTappable).TappedSecondary(&fyne.PointEvent{})
+	assert.NotNil(w.Canvas().Overlays().Top(), "The menu should be shown on the extending widget's canvas")
+}
+
+func TestNewTree_ErrorHandler(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	var handled []error
+	tree := NewTree(WithRoots(&ModelData{Data: "first"}, nil), WithErrorHandler(func(err error) {
+		handled = append(handled, err)
+	}))
+	assert.Len(tree.Children(ModelRoot), 1, "Roots that can be added should still be added")
+	assert.Len(handled, 1)
+	assert.ErrorIs(handled[0], ErrNilData)
+
+	handled = nil
+	dragged, ids, w := buildTree(t, WithDragAndDrop(), WithErrorHandler(func(err error) {
+		handled = append(handled, err)
+	}))
+	defer w.Close()
+	dragged.setDropTarget(DropTarget{ID: ids["a"], Position: DropInto})
+	displayedNode(t, dragged, ids["a"]).self.(fyne.Draggable).DragEnd()
+	assert.Len(handled, 1, "Drop errors should be passed to the error handler")
+	assert.ErrorIs(handled[0], ErrInvalidMove)
+}
+
+func TestNewTreeE(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	tree, err := NewTreeE(WithRoots(&ModelData{Data: "first"}, nil), WithErrorHandler(func(err error) {
+		t.Errorf("Root errors should be returned, not handled: %v", err)
+	}))
+	assert.ErrorIs(err, ErrNilData)
+	assert.Nil(tree)
+
+	tree, err = NewTreeE(WithRoots(&ModelData{Data: "first"}, &ModelData{Data: "second"}))
+	assert.NoError(err)
+	assert.Len(tree.Children(ModelRoot), 2)
+}
+
+func keyNames(actions map[fyne.KeyName]func(widget.TreeNodeID)) []fyne.KeyName {
+	var names []fyne.KeyName
+	for name := range actions {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,113 @@
+package generation
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var _ fyne.Widget = (*TriStateCheck)(nil)
+
+// TriStateCheck is a widget.Check that can also show the Indeterminate state, which is displayed as a dash in the
+// check box. Tapping an indeterminate check will check it.
+type TriStateCheck struct {
+	widget.BaseWidget
+	OnChanged func(checked bool) // OnChanged is called when the user checks or unchecks the box, but not when the state is set programmatically.
+
+	mux   sync.RWMutex
+	state CheckState
+	check *widget.Check
+}
+
+// NewTriStateCheck creates an unchecked TriStateCheck.
+func NewTriStateCheck(changed func(checked bool)) *TriStateCheck {
+	c := &TriStateCheck{
+		OnChanged: changed,
+	}
+	c.check = widget.NewCheck("", func(checked bool) {
+		c.mux.Lock()
+		c.state = Unchecked
+		if checked {
+			c.state = Checked
+		}
+		c.mux.Unlock()
+		c.Refresh()
+		if c.OnChanged != nil {
+			c.OnChanged(checked)
+		}
+	})
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// State returns the currently displayed state.
+func (c *TriStateCheck) State() CheckState {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.state
+}
+
+// SetState changes the displayed state without calling OnChanged.
+func (c *TriStateCheck) SetState(state CheckState) {
+	c.mux.Lock()
+	c.state = state
+	// Setting the field directly avoids widget.Check calling its OnChanged.
+	c.check.Checked = state == Checked
+	c.mux.Unlock()
+	c.check.Refresh()
+	c.Refresh()
+}
+
+func (c *TriStateCheck) CreateRenderer() fyne.WidgetRenderer {
+	render := &triStateCheckRenderer{
+		check: c,
+		mark:  canvas.NewRectangle(theme.ForegroundColor()),
+	}
+	render.objects = []fyne.CanvasObject{c.check, render.mark}
+	render.Refresh()
+	return render
+}
+
+var _ fyne.WidgetRenderer = (*triStateCheckRenderer)(nil)
+
+type triStateCheckRenderer struct {
+	check   *TriStateCheck
+	mark    *canvas.Rectangle
+	objects []fyne.CanvasObject
+}
+
+func (r *triStateCheckRenderer) Destroy() {
+}
+
+func (r *triStateCheckRenderer) Layout(size fyne.Size) {
+	r.check.check.Resize(size)
+	r.check.check.Move(fyne.NewPos(0, 0))
+
+	// This matches where widget.Check places its icon, so the mark is drawn in the middle of the box.
+	iconSize := theme.IconInlineSize()
+	markSize := fyne.NewSize(iconSize/2, iconSize/8)
+	iconPos := fyne.NewPos(theme.Padding()*1.5, (size.Height-iconSize)/2)
+	r.mark.Resize(markSize)
+	r.mark.Move(iconPos.Add(fyne.NewPos((iconSize-markSize.Width)/2, (iconSize-markSize.Height)/2)))
+}
+
+func (r *triStateCheckRenderer) MinSize() fyne.Size {
+	return r.check.check.MinSize()
+}
+
+func (r *triStateCheckRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *triStateCheckRenderer) Refresh() {
+	r.mark.FillColor = theme.ForegroundColor()
+	if r.check.State() == Indeterminate {
+		r.mark.Show()
+	} else {
+		r.mark.Hide()
+	}
+	r.mark.Refresh()
+}
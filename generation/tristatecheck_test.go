@@ -0,0 +1,49 @@
+package generation
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestTriStateCheck_SetState(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	var calls int
+	check := NewTriStateCheck(func(bool) {
+		calls++
+	})
+	render := test.WidgetRenderer(check).(*triStateCheckRenderer)
+	assert.False(render.mark.Visible())
+
+	check.SetState(Indeterminate)
+	assert.Equal(Indeterminate, check.State())
+	assert.False(check.check.Checked)
+	assert.True(render.mark.Visible(), "The mark should show for Indeterminate")
+
+	check.SetState(Checked)
+	assert.True(check.check.Checked)
+	assert.False(render.mark.Visible())
+	assert.Equal(0, calls, "Setting the state should not call OnChanged")
+}
+
+func TestTriStateCheck_Tapped(t *testing.T) {
+	assert := testify.New(t)
+	test.NewApp()
+
+	var changes []bool
+	check := NewTriStateCheck(func(checked bool) {
+		changes = append(changes, checked)
+	})
+	w := test.NewWindow(check)
+	defer w.Close()
+
+	check.SetState(Indeterminate)
+	test.Tap(check.check)
+	assert.Equal(Checked, check.State(), "Tapping an indeterminate check should check it")
+	test.Tap(check.check)
+	assert.Equal(Unchecked, check.State())
+	assert.Equal([]bool{true, false}, changes)
+}
@@ -0,0 +1,43 @@
+package generation
+
+import (
+	"fyne.io/fyne/v2/widget"
+)
+
+// TypedRegistry wraps a TreeModelRegistry to work with a concrete model type instead of TreeModel, so callers don't
+// need to assert registered nodes back to their own type. Models registered through another model's Children that
+// aren't a T are still tracked, but the typed accessors will report them as T's zero value.
+type TypedRegistry[T TreeModel] struct {
+	*TreeModelRegistry
+}
+
+// TypedWalkFunc is the typed equivalent of TreeModelWalkFunc.
+type TypedWalkFunc[T TreeModel] func(parentID widget.TreeNodeID, parent T, nodeID widget.TreeNodeID, node T)
+
+// NewTypedRegistry creates a TypedRegistry with a new, empty TreeModelRegistry.
+func NewTypedRegistry[T TreeModel]() *TypedRegistry[T] {
+	return &TypedRegistry[T]{
+		TreeModelRegistry: NewTreeModelRegistry(),
+	}
+}
+
+// AddChild registers data as a child of parentID, the same as TreeModelRegistry.AddChild.
+func (r *TypedRegistry[T]) AddChild(parentID widget.TreeNodeID, data T) (widget.TreeNodeID, error) {
+	return r.TreeModelRegistry.AddChild(parentID, data)
+}
+
+// Node returns the model registered with nodeID, or T's zero value if it doesn't exist or isn't a T.
+func (r *TypedRegistry[T]) Node(nodeID widget.TreeNodeID) T {
+	node, _ := r.TreeModelRegistry.Node(nodeID).(T)
+	return node
+}
+
+// Walk traverses the registered tree, depth-first. Nodes that aren't a T are passed to walker as T's zero value.
+// Attempting to modify the tree while walking will result in a deadlock.
+func (r *TypedRegistry[T]) Walk(walker TypedWalkFunc[T]) {
+	r.TreeModelRegistry.Walk(func(parentID widget.TreeNodeID, parent TreeModel, nodeID widget.TreeNodeID, node TreeModel) {
+		typedParent, _ := parent.(T)
+		typedNode, _ := node.(T)
+		walker(parentID, typedParent, nodeID, typedNode)
+	})
+}
@@ -0,0 +1,64 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestTypedRegistry_AddChild(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTypedRegistry[*ModelData]()
+	assert.NotNil(reg)
+
+	data := &ModelData{Data: "A"}
+	dataID, err := reg.AddChild(ModelRoot, data)
+	assert.NoError(err)
+	assert.Same(data, reg.Node(dataID), "Node should be returned as the concrete type")
+	assert.Equal(ModelRoot, reg.Parent(dataID), "Untyped accessors should still be available")
+
+	var nilData *ModelData
+	_, err = reg.AddChild(ModelRoot, nilData)
+	assert.True(errors.Is(err, ErrNilData), "Typed nil pointers should be rejected")
+}
+
+func TestTypedRegistry_Node_WrongType(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTypedRegistry[*ModelData]()
+
+	data := &ModelData{Data: "A"}
+	assert.NoError(data.AddChild(&BaseTreeModel{}))
+	dataID, err := reg.AddChild(ModelRoot, data)
+	assert.NoError(err)
+
+	children := reg.Children(dataID)
+	assert.Len(children, 1)
+	assert.Nil(reg.Node(children[0]), "Nodes that aren't a T should be returned as the zero value")
+	assert.NotNil(reg.TreeModelRegistry.Node(children[0]), "The node should still be registered")
+	assert.Nil(reg.Node("does not exist"))
+}
+
+func TestTypedRegistry_Walk(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTypedRegistry[*ModelData]()
+
+	data := &ModelData{Data: "A"}
+	data2 := &ModelData{Data: "B"}
+	dataID, err := reg.AddChild(ModelRoot, data)
+	assert.NoError(err)
+	_, err = reg.AddChild(dataID, data2)
+	assert.NoError(err)
+
+	var visited []string
+	reg.Walk(func(parentID widget.TreeNodeID, parent *ModelData, nodeID widget.TreeNodeID, node *ModelData) {
+		visited = append(visited, node.Data)
+		if parentID == ModelRoot {
+			assert.Nil(parent)
+		} else {
+			assert.Same(data, parent)
+		}
+	})
+	assert.Equal([]string{"A", "B"}, visited)
+}
@@ -0,0 +1,173 @@
+package generation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/pkg/errors"
+)
+
+// ErrInconsistentRegistry is returned by Validate when the registry's structure is broken.
+var ErrInconsistentRegistry = errors.New("inconsistent registry")
+
+// Validate checks the registry's structural invariants, returning an error wrapping ErrInconsistentRegistry that
+// lists every problem found, or nil if there are none. Every registered node but the root has a registered parent
+// that lists it once as a child, can be reached from the root, and has a model that isn't registered under another ID.
+// The children registered for a loaded node match its model's children, in the same order, and only lazy nodes may be
+// unloaded, without registered children.
+func (r *TreeModelRegistry) Validate() error {
+	r.mux.RLock()
+	problems := r.problems()
+	r.mux.RUnlock()
+	if len(problems) == 0 {
+		return nil
+	}
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = problem.message
+	}
+	return errors.Wrapf(ErrInconsistentRegistry, "%d problems found: %s", len(problems), strings.Join(messages, "; "))
+}
+
+// registryProblem is an inconsistency between the registry's maps, or between them and the models' children.
+type registryProblem struct {
+	id      widget.TreeNodeID
+	message string
+}
+
+// problems checks that the registry's maps agree with each other and with the models' children. The registry must be
+// locked.
+func (r *TreeModelRegistry) problems() []registryProblem {
+	var problems []registryProblem
+	add := func(id widget.TreeNodeID, format string, args ...interface{}) {
+		problems = append(problems, registryProblem{id: id, message: fmt.Sprintf(format, args...)})
+	}
+
+	if _, ok := r.idMap[ModelRoot]; !ok {
+		add(ModelRoot, "the root isn't registered")
+	}
+	if parentID, ok := r.parentMap[ModelRoot]; ok {
+		add(ModelRoot, "the root has parent %q", parentID)
+	}
+	if r.idMap[ModelRoot] != nil {
+		add(ModelRoot, "the root has a model")
+	}
+	registeredAs := map[TreeModel]widget.TreeNodeID{}
+	for _, id := range sortedIDs(r.idMap) {
+		if id == ModelRoot {
+			continue
+		}
+		model := r.idMap[id]
+		if isNilModel(model) {
+			add(id, "%q is registered without a model", id)
+		} else if reflect.TypeOf(model).Comparable() {
+			if other, ok := registeredAs[model]; ok {
+				add(id, "the model of %q is also registered as %q", id, other)
+			}
+			registeredAs[model] = id
+		}
+		parentID, ok := r.parentMap[id]
+		switch {
+		case !ok:
+			add(id, "orphan: %q is registered without a parent", id)
+		case !r.isRegistered(parentID):
+			add(id, "dangling parent link: the parent of %q, %q, isn't registered", id, parentID)
+		case !containsID(r.childMap[parentID], id):
+			add(id, "the parent of %q, %q, doesn't list it as a child", id, parentID)
+		}
+	}
+	for _, id := range sortedIDs(r.parentMap) {
+		if !r.isRegistered(id) {
+			add(id, "dangling parent link: %q has parent %q, but isn't registered", id, r.parentMap[id])
+		}
+	}
+	for _, parentID := range sortedIDs(r.childMap) {
+		children := r.childMap[parentID]
+		if !r.isRegistered(parentID) {
+			add(parentID, "children are listed under %q, which isn't registered", parentID)
+		}
+		if len(children) == 0 {
+			add(parentID, "%q has an empty child list", parentID)
+		}
+		if r.unloaded[parentID] {
+			add(parentID, "%q has registered children, but isn't loaded", parentID)
+		}
+		listed := map[widget.TreeNodeID]bool{}
+		for _, id := range children {
+			if listed[id] {
+				add(id, "%q is listed more than once under %q", id, parentID)
+			}
+			listed[id] = true
+			if actual, ok := r.parentMap[id]; !r.isRegistered(id) {
+				add(id, "%q is listed under %q, but isn't registered", id, parentID)
+			} else if ok && actual != parentID {
+				add(id, "%q is listed under %q, but its parent is %q", id, parentID, actual)
+			}
+		}
+	}
+	for _, id := range sortedIDs(r.unloaded) {
+		if !r.isRegistered(id) {
+			add(id, "%q is marked as not loaded, but isn't registered", id)
+		} else if _, lazy := r.idMap[id].(LazyTreeModel); !lazy {
+			add(id, "%q is marked as not loaded, but its model isn't a LazyTreeModel", id)
+		}
+	}
+	for _, id := range sortedIDs(r.idMap) {
+		if model := r.idMap[id]; id != ModelRoot && !isNilModel(model) && !r.unloaded[id] {
+			if message := r.childMismatch(id, model); message != "" {
+				add(id, message)
+			}
+		}
+	}
+
+	reached := map[widget.TreeNodeID]bool{ModelRoot: true}
+	pending := []widget.TreeNodeID{ModelRoot}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		for _, child := range r.childMap[id] {
+			if !reached[child] {
+				reached[child] = true
+				pending = append(pending, child)
+			}
+		}
+	}
+	for _, id := range sortedIDs(r.idMap) {
+		if _, hasParent := r.parentMap[id]; !reached[id] && hasParent {
+			add(id, "%q can't be reached from the root", id)
+		}
+	}
+	return problems
+}
+
+// childMismatch describes how the children registered for id differ from its model's children, or returns an empty
+// string if they're the same.
+func (r *TreeModelRegistry) childMismatch(id widget.TreeNodeID, model TreeModel) string {
+	modelChildren := model.Children()
+	registered := r.childMap[id]
+	if len(modelChildren) != len(registered) {
+		return fmt.Sprintf("the model of %q has %d children, but %d are registered", id, len(modelChildren), len(registered))
+	}
+	for i, child := range modelChildren {
+		if r.idMap[registered[i]] != child {
+			return fmt.Sprintf("the children registered for %q don't match its model's children at index %d", id, i)
+		}
+	}
+	return ""
+}
+
+func (r *TreeModelRegistry) isRegistered(id widget.TreeNodeID) bool {
+	_, ok := r.idMap[id]
+	return ok
+}
+
+func containsID(ids []widget.TreeNodeID, id widget.TreeNodeID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
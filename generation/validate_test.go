@@ -0,0 +1,51 @@
+package generation
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestTreeModelRegistry_Validate(t *testing.T) {
+	assert := testify.New(t)
+	reg, ids := buildCheckTree(t)
+	assert.NoError(reg.Validate())
+	assert.NoError(NewTreeModelRegistry().Validate())
+
+	a := reg.Node(ids["a"])
+	assert.NoError(a.AddChild(&ModelData{Data: "unregistered"}))
+	err := reg.Validate()
+	assert.ErrorIs(err, ErrInconsistentRegistry)
+	assert.Contains(err.Error(), "1 problems found: the model of")
+	assert.NotNil(a.RemoveChildAt(2))
+	assert.NoError(reg.Validate())
+
+	reg.idMap["copy"] = a
+	reg.parentMap["copy"] = ids["b"]
+	reg.childMap[ids["b"]] = append(reg.childMap[ids["b"]], "copy")
+	err = reg.Validate()
+	assert.ErrorIs(err, ErrInconsistentRegistry)
+	assert.Contains(err.Error(), "is also registered as")
+}
+
+func TestTreeModelRegistry_ValidateLazy(t *testing.T) {
+	assert := testify.New(t)
+	reg := NewTreeModelRegistry()
+	reg.SetIDStrategy(PathStrategy)
+	lazy := &lazyModel{ModelData: ModelData{Data: "lazy"}}
+	assert.NoError(lazy.AddChild(&ModelData{Data: "a"}))
+	_, err := reg.AddChild(ModelRoot, lazy)
+	assert.NoError(err)
+	assert.NoError(reg.Validate(), "Unloaded nodes shouldn't be compared with their model's children")
+	reg.Load("lazy")
+	assert.NoError(reg.Validate())
+
+	_, err = reg.AddChild(ModelRoot, &ModelData{Data: "eager"})
+	assert.NoError(err)
+	reg.unloaded["eager"] = true
+	reg.unloaded["lazy"] = true
+	err = reg.Validate()
+	assert.ErrorIs(err, ErrInconsistentRegistry)
+	assert.Contains(err.Error(), `"eager" is marked as not loaded, but its model isn't a LazyTreeModel`)
+	assert.Contains(err.Error(), `"lazy" has registered children, but isn't loaded`)
+}
@@ -0,0 +1,391 @@
+package generation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+var _ LazyTreeModel = (*ValueModel)(nil)
+var _ SubtitledTreeModel = (*ValueModel)(nil)
+var _ ChildPolicy = (*ValueModel)(nil)
+
+// valuePageSize is the most elements or entries shown directly under a collection. Larger collections are split into
+// pages of this many, which are each read when they're opened.
+const valuePageSize = 100
+
+// ValueModel is a read-only LazyTreeModel for a Go value, created by ValueTreeModel.
+type ValueModel struct {
+	BaseTreeModel
+
+	parent *ValueModel
+	label  string
+	value  reflect.Value // value is the value as found in its parent.
+	elem   reflect.Value // elem is value with pointers and interfaces followed.
+	refs   []valueRef    // refs identify the pointers, maps and slices followed to reach elem.
+	cycle  bool          // cycle is set if elem was already reached by an ancestor, so it isn't shown again.
+	page   *valuePage    // page is set for nodes holding part of a large collection.
+
+	mux    sync.Mutex
+	loaded bool
+}
+
+type valueRef struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+type valuePage struct {
+	start, end int
+	keys       []reflect.Value // keys are the sorted keys of a map.
+}
+
+// ValueTreeModel creates a model for inspecting v with reflection. Structs have a child for each field, maps for each
+// entry, sorted by key, and slices and arrays for each element, while pointers and interfaces are followed to the
+// value they refer to. Labels show the field name, key or index, and the value of scalars and fmt.Stringer
+// implementations, with the type in the subtitle.
+//
+// Children are read when a node is first loaded, and collections with more than 100 elements are split into pages
+// that are loaded separately. A pointer, map or slice that's already shown by an ancestor is marked as a cycle instead
+// of being followed again.
+//
+// Struct fields may be tagged to change how they're shown, with options separated by commas:
+//
+//	Secret string `tree:"-"`                  // Left out.
+//	Label  string `tree:"name=Display name"`  // Shown as "Display name".
+//	Notes  string `tree:"omitempty"`          // Left out when it's the zero value.
+func ValueTreeModel(v interface{}) *ValueModel {
+	return newValueModel(nil, "", reflect.ValueOf(v))
+}
+
+func newValueModel(parent *ValueModel, label string, value reflect.Value) *ValueModel {
+	m := &ValueModel{parent: parent, label: label, value: value}
+	elem := value
+	for (elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface) && !elem.IsNil() {
+		if elem.Kind() == reflect.Ptr {
+			m.refs = append(m.refs, valueRef{typ: elem.Type(), ptr: elem.Pointer()})
+		}
+		elem = elem.Elem()
+	}
+	if (elem.Kind() == reflect.Map || elem.Kind() == reflect.Slice) && elem.Len() > 0 {
+		m.refs = append(m.refs, valueRef{typ: elem.Type(), ptr: elem.Pointer(), len: elem.Len()})
+	}
+	m.elem = elem
+	for ancestor := parent; ancestor != nil && !m.cycle; ancestor = ancestor.parent {
+		for _, ref := range m.refs {
+			for _, ancestorRef := range ancestor.refs {
+				if ref == ancestorRef {
+					m.cycle = true
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Value returns the value shown by the model, as found in its parent.
+func (m *ValueModel) Value() reflect.Value {
+	return m.value
+}
+
+func (m *ValueModel) MayHaveChildren() bool {
+	if m.cycle {
+		return false
+	}
+	if m.page != nil {
+		return true
+	}
+	switch m.elem.Kind() {
+	case reflect.Struct:
+		return m.elem.NumField() > 0
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return m.elem.Len() > 0
+	default:
+		return false
+	}
+}
+
+// Children reads the value's fields, elements or entries the first time it's called.
+func (m *ValueModel) Children() []TreeModel {
+	m.load()
+	return m.BaseTreeModel.Children()
+}
+
+// CanAccept refuses every child, since values can't be changed through the tree.
+func (m *ValueModel) CanAccept(TreeModel) bool {
+	return false
+}
+
+func (m *ValueModel) MaxChildren() int {
+	return 0
+}
+
+func (m *ValueModel) AllowedTypes() []TreeModel {
+	return nil
+}
+
+func (m *ValueModel) DisplayIcon() fyne.Resource {
+	switch {
+	case m.cycle:
+		return theme.ViewRefreshIcon()
+	case m.page != nil:
+		return theme.MoreHorizontalIcon()
+	}
+	switch m.elem.Kind() {
+	case reflect.Invalid, reflect.Ptr, reflect.Interface:
+		return theme.ContentClearIcon()
+	case reflect.Struct:
+		return theme.GridIcon()
+	case reflect.Slice, reflect.Array:
+		return theme.ListIcon()
+	case reflect.Map:
+		return theme.MenuIcon()
+	case reflect.String:
+		return theme.FileTextIcon()
+	case reflect.Bool:
+		if m.elem.Bool() {
+			return theme.CheckButtonCheckedIcon()
+		}
+		return theme.CheckButtonIcon()
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return theme.SettingsIcon()
+	default:
+		return theme.ContentAddIcon()
+	}
+}
+
+// DisplayString shows the field name, key or index of the model, followed by its value if it has a short form. The
+// root is shown as its value, or its type if it doesn't have one.
+func (m *ValueModel) DisplayString() string {
+	summary := m.summary()
+	switch {
+	case m.label == "" && summary == "":
+		return m.typeName()
+	case m.label == "":
+		return summary
+	case summary == "":
+		return m.label
+	default:
+		return m.label + ": " + summary
+	}
+}
+
+// DisplaySubtitle shows the type of the value, with the length of collections.
+func (m *ValueModel) DisplaySubtitle() string {
+	if m.page != nil {
+		return fmt.Sprintf("%d of %d", m.page.end-m.page.start, m.elem.Len())
+	}
+	switch m.elem.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("%s (%d)", m.typeName(), m.elem.Len())
+	default:
+		return m.typeName()
+	}
+}
+
+// typeName is the type of the value, or the type it holds if it's a non-nil interface.
+func (m *ValueModel) typeName() string {
+	value := m.value
+	if value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return "nil"
+	}
+	return value.Type().String()
+}
+
+// summary is a short form of the value, or empty if it doesn't have one.
+func (m *ValueModel) summary() string {
+	switch {
+	case m.cycle:
+		return "(cycle)"
+	case m.page != nil:
+		return ""
+	case !m.elem.IsValid():
+		return "nil"
+	}
+	text, _ := formatScalar(m.elem)
+	if text == "nil" {
+		return text
+	}
+	if stringText, ok := stringerText(m.value); ok {
+		return stringText
+	}
+	return text
+}
+
+// stringerText calls Error or String on value if it implements error or fmt.Stringer. Panics, like those from nil
+// receivers, are shown instead.
+func stringerText(value reflect.Value) (text string, ok bool) {
+	if !value.IsValid() || !value.CanInterface() {
+		return "", false
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			text, ok = fmt.Sprintf("(panic: %v)", r), true
+		}
+	}()
+	switch v := value.Interface().(type) {
+	case error:
+		return v.Error(), true
+	case fmt.Stringer:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
+
+// formatScalar formats values that aren't collections or structs, and reports whether value is one.
+func formatScalar(value reflect.Value) (string, bool) {
+	switch value.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), true
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(value.Complex(), 'g', -1, value.Type().Bits()), true
+	case reflect.String:
+		return strconv.Quote(value.String()), true
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if value.IsNil() {
+			return "nil", true
+		}
+		return fmt.Sprintf("%#x", value.Pointer()), true
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			return "nil", true
+		}
+	}
+	return "", false
+}
+
+func (m *ValueModel) load() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.loaded || m.cycle {
+		return
+	}
+	m.loaded = true
+	for _, child := range m.readChildren() {
+		_ = m.BaseTreeModel.AddChild(child)
+	}
+}
+
+func (m *ValueModel) readChildren() []*ValueModel {
+	var children []*ValueModel
+	switch m.elem.Kind() {
+	case reflect.Struct:
+		t := m.elem.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if label, ok := fieldLabel(t.Field(i), m.elem.Field(i)); ok {
+				children = append(children, newValueModel(m, label, m.elem.Field(i)))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		start, end := 0, m.elem.Len()
+		if m.page != nil {
+			start, end = m.page.start, m.page.end
+		} else if end > valuePageSize {
+			return m.pages(end, nil)
+		}
+		for i := start; i < end; i++ {
+			children = append(children, newValueModel(m, fmt.Sprintf("[%d]", i), m.elem.Index(i)))
+		}
+	case reflect.Map:
+		var keys []reflect.Value
+		if m.page != nil {
+			keys = m.page.keys[m.page.start:m.page.end]
+		} else if keys = sortedKeys(m.elem); len(keys) > valuePageSize {
+			return m.pages(len(keys), keys)
+		}
+		for _, key := range keys {
+			children = append(children, newValueModel(m, "["+formatKey(key)+"]", m.elem.MapIndex(key)))
+		}
+	}
+	return children
+}
+
+// pages splits a collection of length items into pages, which share the value of m.
+func (m *ValueModel) pages(length int, keys []reflect.Value) []*ValueModel {
+	var pages []*ValueModel
+	for start := 0; start < length; start += valuePageSize {
+		end := start + valuePageSize
+		if end > length {
+			end = length
+		}
+		pages = append(pages, &ValueModel{
+			parent: m,
+			label:  fmt.Sprintf("[%d..%d]", start, end-1),
+			value:  m.value,
+			elem:   m.elem,
+			page:   &valuePage{start: start, end: end, keys: keys},
+		})
+	}
+	return pages
+}
+
+// fieldLabel returns the label for a struct field, and false if its tree tag leaves it out.
+func fieldLabel(field reflect.StructField, value reflect.Value) (string, bool) {
+	label := field.Name
+	tag, ok := field.Tag.Lookup("tree")
+	if !ok {
+		return label, true
+	}
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "-":
+			return "", false
+		case option == "omitempty" && value.IsZero():
+			return "", false
+		case strings.HasPrefix(option, "name="):
+			label = strings.TrimPrefix(option, "name=")
+		}
+	}
+	return label, true
+}
+
+func formatKey(key reflect.Value) string {
+	if text, ok := formatScalar(key); ok {
+		return text
+	}
+	if text, ok := stringerText(key); ok {
+		return text
+	}
+	if key.CanInterface() {
+		return fmt.Sprintf("%v", key.Interface())
+	}
+	return key.Type().String()
+}
+
+// sortedKeys returns the keys of a map, with numbers sorted by value and anything else by how it's formatted.
+func sortedKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Kind() == b.Kind() {
+			switch a.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return a.Int() < b.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return a.Uint() < b.Uint()
+			case reflect.Float32, reflect.Float64:
+				return a.Float() < b.Float()
+			}
+		}
+		return formatKey(a) < formatKey(b)
+	})
+	return keys
+}
@@ -0,0 +1,147 @@
+package generation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/theme"
+	testify "github.com/stretchr/testify/require"
+)
+
+type valueNode struct {
+	Name     string
+	Count    int
+	Ratio    float32
+	Enabled  bool
+	Tags     []string
+	Limits   map[string]int
+	Next     *valueNode
+	Any      interface{}
+	Err      error
+	Timeout  time.Duration
+	Secret   string `tree:"-"`
+	Title    string `tree:"name=Display title"`
+	Notes    string `tree:"omitempty,name=Remarks"`
+	internal int
+}
+
+func TestValueTreeModel(t *testing.T) {
+	assert := testify.New(t)
+	node := &valueNode{
+		Name:     "root",
+		Count:    3,
+		Ratio:    0.5,
+		Enabled:  true,
+		Tags:     []string{"a", "b"},
+		Limits:   map[string]int{"b": 2, "a": 1},
+		Any:      uint8(7),
+		Err:      errors.New("failed"),
+		Timeout:  time.Second,
+		Secret:   "hidden",
+		Title:    "Shown",
+		internal: 9,
+	}
+	root := ValueTreeModel(node)
+	assert.Equal("*generation.valueNode", root.DisplayString(), "Roots without a short form should show their type")
+	assert.Equal("*generation.valueNode", root.DisplaySubtitle())
+	assert.Equal(theme.GridIcon(), root.DisplayIcon())
+	assert.True(root.MayHaveChildren())
+
+	children := root.Children()
+	assert.Equal([]string{
+		`Name: "root"`,
+		"Count: 3",
+		"Ratio: 0.5",
+		"Enabled: true",
+		"Tags",
+		"Limits",
+		"Next: nil",
+		"Any: 7",
+		"Err: failed",
+		"Timeout: 1s",
+		`Display title: "Shown"`,
+		"internal: 9",
+	}, displayStrings(children), "Tagged fields should be renamed or left out")
+	assert.Equal("uint8", children[7].(*ValueModel).DisplaySubtitle(), "Interfaces should show the type they hold")
+	assert.Equal("[]string (2)", children[4].(*ValueModel).DisplaySubtitle())
+	assert.Equal([]string{`[0]: "a"`, `[1]: "b"`}, displayStrings(children[4].Children()))
+	assert.Equal([]string{`["a"]: 1`, `["b"]: 2`}, displayStrings(children[5].Children()), "Map keys should be sorted")
+	assert.Equal(theme.ContentClearIcon(), children[6].DisplayIcon())
+	assert.False(children[6].(*ValueModel).MayHaveChildren())
+	assert.Empty(children[0].Children())
+
+	node.Notes = "note"
+	assert.Contains(displayStrings(ValueTreeModel(node).Children()), `Remarks: "note"`)
+	assert.Equal("nil", ValueTreeModel(nil).DisplayString())
+	assert.Equal("42", ValueTreeModel(42).DisplayString())
+	reg := NewTreeModelRegistry()
+	rootID, err := reg.AddChild(ModelRoot, ValueTreeModel(node))
+	assert.NoError(err)
+	_, err = reg.AddChild(rootID, ValueTreeModel(1))
+	assert.Error(err, "Values can't be changed through the tree")
+}
+
+func TestValueTreeModel_Cycles(t *testing.T) {
+	assert := testify.New(t)
+	a := &valueNode{Name: "a"}
+	b := &valueNode{Name: "b", Next: a}
+	a.Next = b
+	self := map[string]interface{}{}
+	self["self"] = self
+	a.Any = self
+
+	root := ValueTreeModel(a)
+	next := root.Children()[6]
+	assert.Equal("Next", next.DisplayString())
+	cycle := next.Children()[6].(*ValueModel)
+	assert.Equal("Next: (cycle)", cycle.DisplayString(), "Pointers shown by an ancestor shouldn't be followed")
+	assert.Equal(theme.ViewRefreshIcon(), cycle.DisplayIcon())
+	assert.False(cycle.MayHaveChildren())
+	assert.Empty(cycle.Children())
+	assert.Equal([]string{`["self"]: (cycle)`}, displayStrings(root.Children()[7].Children()))
+
+	shared := &valueNode{Name: "shared"}
+	pair := []*valueNode{shared, shared}
+	assert.Equal([]string{"[0]", "[1]"}, displayStrings(ValueTreeModel(pair).Children()), "Values shown twice by siblings aren't cycles")
+}
+
+func TestValueTreeModel_Pages(t *testing.T) {
+	assert := testify.New(t)
+	values := make([]int, 250)
+	for i := range values {
+		values[i] = i
+	}
+	root := ValueTreeModel(values)
+	assert.Equal("[]int", root.DisplayString())
+	pages := root.Children()
+	assert.Equal([]string{"[0..99]", "[100..199]", "[200..249]"}, displayStrings(pages))
+	assert.Equal("50 of 250", pages[2].(*ValueModel).DisplaySubtitle())
+	assert.True(pages[2].(*ValueModel).MayHaveChildren())
+	last := pages[2].Children()
+	assert.Len(last, 50)
+	assert.Equal("[249]: 249", last[49].DisplayString())
+
+	entries := map[int]string{}
+	for i := 0; i < 150; i++ {
+		entries[i] = fmt.Sprint(i)
+	}
+	mapPages := ValueTreeModel(entries).Children()
+	assert.Len(mapPages, 2)
+	assert.Equal(`[100]: "100"`, mapPages[1].Children()[0].DisplayString(), "Numeric keys should be sorted by value")
+}
+
+func TestValueTreeModel_Lazy(t *testing.T) {
+	assert := testify.New(t)
+	tree := NewTree(WithRoots(ValueTreeModel(&valueNode{Tags: []string{"a"}})))
+	rootID := tree.Children(ModelRoot)[0]
+	assert.False(tree.IsLoaded(rootID))
+	assert.True(tree.IsBranch(rootID))
+	tree.OpenBranch(rootID)
+	assert.Len(tree.Children(rootID), 12)
+	tagsID := tree.Children(rootID)[4]
+	assert.False(tree.IsLoaded(tagsID))
+	tree.OpenBranch(tagsID)
+	assert.Len(tree.Children(tagsID), 1)
+}
@@ -0,0 +1,50 @@
+package generation
+
+import (
+	"encoding/json"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ViewState is the state of a tree's display, keyed by node ID, so it can be saved and restored. It's only useful
+// between runs if the registry's IDStrategy creates stable IDs, like PathStrategy or ModelIDStrategy.
+type ViewState struct {
+	Open      []widget.TreeNodeID `json:"open,omitempty"`      // Open lists the open branches.
+	Selected  []widget.TreeNodeID `json:"selected,omitempty"`  // Selected lists the selected nodes.
+	ScrollTop widget.TreeNodeID   `json:"scrollTop,omitempty"` // ScrollTop is the node at the top of the tree, which stands in for the scroll offset that widget.Tree doesn't expose.
+}
+
+// Write writes the state to w as JSON.
+func (s ViewState) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// ReadViewState reads a state written by ViewState.Write.
+func ReadViewState(r io.Reader) (ViewState, error) {
+	var state ViewState
+	err := json.NewDecoder(r).Decode(&state)
+	return state, err
+}
+
+// Store saves the state in prefs under key.
+func (s ViewState) Store(prefs fyne.Preferences, key string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	prefs.SetString(key, string(data))
+	return nil
+}
+
+// LoadViewState loads a state saved with ViewState.Store. An empty state is returned if there's nothing saved under key.
+func LoadViewState(prefs fyne.Preferences, key string) (ViewState, error) {
+	var state ViewState
+	data := prefs.String(key)
+	if data == "" {
+		return state, nil
+	}
+	err := json.Unmarshal([]byte(data), &state)
+	return state, err
+}
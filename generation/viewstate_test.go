@@ -0,0 +1,50 @@
+package generation
+
+import (
+	"bytes"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	testify "github.com/stretchr/testify/require"
+)
+
+func TestViewState_Write(t *testing.T) {
+	assert := testify.New(t)
+	state := ViewState{
+		Open:      []widget.TreeNodeID{"root", "root/a"},
+		Selected:  []widget.TreeNodeID{"root/a/a1"},
+		ScrollTop: "root/a",
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(state.Write(&buf))
+	read, err := ReadViewState(&buf)
+	assert.NoError(err)
+	assert.Equal(state, read)
+
+	_, err = ReadViewState(bytes.NewBufferString("not json"))
+	assert.Error(err)
+}
+
+func TestViewState_Store(t *testing.T) {
+	assert := testify.New(t)
+	prefs := test.NewApp().Preferences()
+	state := ViewState{
+		Open:     []widget.TreeNodeID{"root"},
+		Selected: []widget.TreeNodeID{"root/a", "root/b"},
+	}
+
+	empty, err := LoadViewState(prefs, "tree")
+	assert.NoError(err)
+	assert.Equal(ViewState{}, empty, "Nothing saved should load an empty state")
+
+	assert.NoError(state.Store(prefs, "tree"))
+	loaded, err := LoadViewState(prefs, "tree")
+	assert.NoError(err)
+	assert.Equal(state, loaded)
+
+	prefs.SetString("tree", "{")
+	_, err = LoadViewState(prefs, "tree")
+	assert.Error(err)
+}
@@ -0,0 +1,556 @@
+package generation
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"github.com/pkg/errors"
+)
+
+var _ RenamableTreeModel = (*XMLTreeModel)(nil)
+var _ SubtitledTreeModel = (*XMLTreeModel)(nil)
+var _ ChildPolicy = (*XMLTreeModel)(nil)
+
+var (
+	ErrInvalidXMLName = errors.New("invalid XML name")
+	ErrDuplicateAttr  = errors.New("duplicate attribute")
+	ErrNotEditable    = errors.New("the node can't be edited")
+)
+
+// XMLKind is the kind of node held by an XMLTreeModel.
+type XMLKind int
+
+const (
+	XMLDocument XMLKind = iota
+	XMLElement
+	XMLAttr
+	XMLText
+	XMLComment
+	XMLProcInst
+	XMLDirective
+)
+
+func (k XMLKind) String() string {
+	switch k {
+	case XMLDocument:
+		return "document"
+	case XMLElement:
+		return "element"
+	case XMLAttr:
+		return "attribute"
+	case XMLText:
+		return "text"
+	case XMLComment:
+		return "comment"
+	case XMLProcInst:
+		return "processing instruction"
+	default:
+		return "directive"
+	}
+}
+
+// XMLTreeModel is a node of an XML document. Elements have a child for each of their attributes, followed by their
+// content, and the document has a child for its root element and anything around it. Names keep their namespace
+// prefix, and CDATA sections are kept as text that's written back out as CDATA. Text that's only whitespace is left
+// out of elements that don't contain any other text, since it only lays out their children, but is kept in mixed
+// content. Elements, attributes, text and comments can be edited by renaming them, and nodes can be moved, added and
+// removed through the registry, with the changes written back out by Write.
+type XMLTreeModel struct {
+	BaseTreeModel
+
+	mux    sync.RWMutex
+	kind   XMLKind
+	name   string // name is the prefixed name of an element or attribute, or the target of a processing instruction.
+	value  string // value is the value of an attribute, or the content of any other node but an element.
+	cdata  bool   // cdata is set for text read from a CDATA section, so it's written back out as one.
+	closed bool   // closed is set for elements read from an empty-element tag, so they're written as one while empty.
+	parent *XMLTreeModel
+}
+
+// ReadXML reads an XML document from r.
+func ReadXML(r io.Reader) (*XMLTreeModel, error) {
+	// The input is kept so CDATA sections can be told apart from other text, which the decoder doesn't do.
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	doc := NewXMLDocument()
+	stack := []*XMLTreeModel{doc}
+	for {
+		// Raw tokens keep the prefixes of names, so they can be written back out the same way.
+		start := dec.InputOffset()
+		token, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		top := stack[len(stack)-1]
+		var node *XMLTreeModel
+		switch t := token.(type) {
+		case xml.StartElement:
+			node = &XMLTreeModel{kind: XMLElement, name: prefixedName(t.Name), closed: bytes.HasSuffix(data[start:dec.InputOffset()], []byte("/>"))}
+			for _, attr := range t.Attr {
+				_ = node.AddChild(&XMLTreeModel{kind: XMLAttr, name: prefixedName(attr.Name), value: attr.Value})
+			}
+			_ = top.AddChild(node)
+			stack = append(stack, node)
+			continue
+		case xml.EndElement:
+			if name := prefixedName(t.Name); top.kind != XMLElement || top.name != name {
+				return nil, fmt.Errorf("offset %d: unexpected end element </%s>", dec.InputOffset(), name)
+			}
+			top.dropLayout()
+			stack = stack[:len(stack)-1]
+			continue
+		case xml.CharData:
+			cdata := bytes.HasPrefix(data[start:dec.InputOffset()], []byte("<![CDATA["))
+			if top == doc {
+				if !cdata && strings.TrimSpace(string(t)) == "" {
+					continue
+				}
+				return nil, errors.New("text outside the root element")
+			}
+			node = &XMLTreeModel{kind: XMLText, value: string(t), cdata: cdata}
+		case xml.Comment:
+			node = &XMLTreeModel{kind: XMLComment, value: string(t)}
+		case xml.ProcInst:
+			node = &XMLTreeModel{kind: XMLProcInst, name: t.Target, value: string(t.Inst)}
+		case xml.Directive:
+			node = &XMLTreeModel{kind: XMLDirective, value: string(t)}
+		}
+		_ = top.AddChild(node)
+	}
+	if len(stack) > 1 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return doc, nil
+}
+
+// dropLayout removes whitespace-only text from an element with no other text, since it only lays out the element's
+// children, and Write indents them again.
+func (m *XMLTreeModel) dropLayout() {
+	children := m.Children()
+	for _, child := range children {
+		if text, ok := child.(*XMLTreeModel); ok && text.kind == XMLText && (text.cdata || strings.TrimSpace(text.value) != "") {
+			return
+		}
+	}
+	for i := len(children) - 1; i >= 0; i-- {
+		if text, ok := children[i].(*XMLTreeModel); ok && text.kind == XMLText {
+			m.RemoveChildAt(i)
+		}
+	}
+}
+
+func prefixedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// NewXMLDocument creates an empty document.
+func NewXMLDocument() *XMLTreeModel {
+	return &XMLTreeModel{kind: XMLDocument}
+}
+
+// NewXMLElement creates an element called name, which may have a namespace prefix.
+func NewXMLElement(name string) (*XMLTreeModel, error) {
+	if err := validXMLName(name); err != nil {
+		return nil, err
+	}
+	return &XMLTreeModel{kind: XMLElement, name: name}, nil
+}
+
+// NewXMLAttr creates an attribute to be added to an element.
+func NewXMLAttr(name, value string) (*XMLTreeModel, error) {
+	if err := validXMLName(name); err != nil {
+		return nil, err
+	}
+	return &XMLTreeModel{kind: XMLAttr, name: name, value: value}, nil
+}
+
+// NewXMLText creates text to be added to an element.
+func NewXMLText(text string) *XMLTreeModel {
+	return &XMLTreeModel{kind: XMLText, value: text}
+}
+
+// NewXMLComment creates a comment, which can't contain "--".
+func NewXMLComment(text string) (*XMLTreeModel, error) {
+	if strings.Contains(text, "--") {
+		return nil, errors.New(`comments can't contain "--"`)
+	}
+	return &XMLTreeModel{kind: XMLComment, value: text}, nil
+}
+
+// Kind returns the kind of node held by the model.
+func (m *XMLTreeModel) Kind() XMLKind {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.kind
+}
+
+// Name returns the prefixed name of an element or attribute, or the target of a processing instruction.
+func (m *XMLTreeModel) Name() string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.name
+}
+
+// Value returns the value of an attribute, or the content of text, comments, processing instructions and directives.
+func (m *XMLTreeModel) Value() string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.value
+}
+
+// Write writes the model and its descendants to w as XML. Elements that only contain other nodes have each of them
+// written on its own line and indented, while the content of elements containing text is written as it is, so an
+// unedited document read by ReadXML is written back out the same way if it was indented by two spaces. Empty-element
+// tags are kept while the element stays empty.
+func (m *XMLTreeModel) Write(w io.Writer) error {
+	nodes := []*XMLTreeModel{m}
+	if m.Kind() == XMLDocument {
+		// Each node around the root element is written on its own line.
+		nodes = m.xmlChildren()
+	}
+	for _, node := range nodes {
+		enc := xml.NewEncoder(w)
+		if err := node.encode(enc, w, 0, false); err != nil {
+			return err
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encode writes the model's tokens to enc, which writes to w. Prefixed names are written as they are, without namespace
+// translation. Content is indented by depth unless inline is set, which it is inside elements that contain text.
+func (m *XMLTreeModel) encode(enc *xml.Encoder, w io.Writer, depth int, inline bool) error {
+	kind, name, value := m.node()
+	switch kind {
+	case XMLElement:
+		start := xml.StartElement{Name: xml.Name{Local: name}}
+		var content []*XMLTreeModel
+		for _, child := range m.xmlChildren() {
+			switch childKind, childName, childValue := child.node(); childKind {
+			case XMLAttr:
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: childName}, Value: childValue})
+			case XMLText:
+				inline = true
+				content = append(content, child)
+			default:
+				content = append(content, child)
+			}
+		}
+		if len(content) == 0 && m.isClosed() {
+			return writeEmptyElement(enc, w, start)
+		}
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, child := range content {
+			if !inline {
+				if err := enc.EncodeToken(xml.CharData("\n" + strings.Repeat("  ", depth+1))); err != nil {
+					return err
+				}
+			}
+			if err := child.encode(enc, w, depth+1, inline); err != nil {
+				return err
+			}
+		}
+		if !inline && len(content) > 0 {
+			if err := enc.EncodeToken(xml.CharData("\n" + strings.Repeat("  ", depth))); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case XMLText:
+		if !m.isCDATA() {
+			return enc.EncodeToken(xml.CharData(value))
+		}
+		// The encoder can't write CDATA, so it's written straight to w once the encoder has caught up.
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "<![CDATA["+strings.ReplaceAll(value, "]]>", "]]]]><![CDATA[>")+"]]>")
+		return err
+	case XMLComment:
+		return enc.EncodeToken(xml.Comment(value))
+	case XMLProcInst:
+		return enc.EncodeToken(xml.ProcInst{Target: name, Inst: []byte(value)})
+	case XMLDirective:
+		return enc.EncodeToken(xml.Directive(value))
+	default:
+		// Attributes are written by their element, and documents by Write.
+		return nil
+	}
+}
+
+// writeEmptyElement writes start as an empty-element tag straight to w, since the encoder always writes an end tag.
+func writeEmptyElement(enc *xml.Encoder, w io.Writer, start xml.StartElement) error {
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("<" + start.Name.Local)
+	for _, attr := range start.Attr {
+		buf.WriteString(" " + attr.Name.Local + `="`)
+		if err := xml.EscapeText(&buf, []byte(attr.Value)); err != nil {
+			return err
+		}
+		buf.WriteString(`"`)
+	}
+	buf.WriteString("/>")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func (m *XMLTreeModel) isClosed() bool {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.closed
+}
+
+func (m *XMLTreeModel) isCDATA() bool {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.cdata
+}
+
+func (m *XMLTreeModel) DisplayIcon() fyne.Resource {
+	switch m.Kind() {
+	case XMLDocument:
+		return theme.DocumentIcon()
+	case XMLElement:
+		return theme.ListIcon()
+	case XMLAttr:
+		return theme.MoreHorizontalIcon()
+	case XMLText:
+		return theme.FileTextIcon()
+	case XMLComment:
+		return theme.InfoIcon()
+	default:
+		return theme.SettingsIcon()
+	}
+}
+
+// DisplayString shows elements as a tag, attributes as they're written in one, and text quoted.
+func (m *XMLTreeModel) DisplayString() string {
+	switch kind, name, value := m.node(); kind {
+	case XMLDocument:
+		return "#document"
+	case XMLElement:
+		return "<" + name + ">"
+	case XMLAttr:
+		return fmt.Sprintf("%s=%q", name, value)
+	case XMLText:
+		return strconv.Quote(strings.TrimSpace(value))
+	case XMLComment:
+		return "<!--" + value + "-->"
+	case XMLProcInst:
+		return fmt.Sprintf("<?%s %s?>", name, value)
+	default:
+		return "<!" + value + ">"
+	}
+}
+
+// DisplaySubtitle shows the kind of node.
+func (m *XMLTreeModel) DisplaySubtitle() string {
+	return m.Kind().String()
+}
+
+// SetDisplayString renames elements, changes the name and value of attributes, and changes the content of text and
+// comments, given text like the display string. Other nodes can't be edited.
+func (m *XMLTreeModel) SetDisplayString(text string) error {
+	kind, name, value := m.node()
+	switch kind {
+	case XMLElement:
+		name = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(text), "<"), ">"))
+		if err := validXMLName(name); err != nil {
+			return err
+		}
+	case XMLAttr:
+		name = text
+		if i := strings.Index(text, "="); i >= 0 {
+			name, value = text[:i], unquote(strings.TrimSpace(text[i+1:]))
+		}
+		name = strings.TrimSpace(name)
+		if err := validXMLName(name); err != nil {
+			return err
+		}
+		if parent := m.getParent(); parent != nil && parent.hasAttr(name, m) {
+			return errors.Wrapf(ErrDuplicateAttr, "'%s'", name)
+		}
+	case XMLText:
+		value = unquote(strings.TrimSpace(text))
+	case XMLComment:
+		value = strings.TrimSuffix(strings.TrimPrefix(text, "<!--"), "-->")
+		if strings.Contains(value, "--") {
+			return errors.New(`comments can't contain "--"`)
+		}
+	default:
+		return errors.Wrapf(ErrNotEditable, "%s", kind)
+	}
+	m.mux.Lock()
+	m.name, m.value = name, value
+	m.mux.Unlock()
+	m.NotifyChanged()
+	return nil
+}
+
+// unquote removes the quotes around text, if it has them.
+func unquote(text string) string {
+	if len(text) < 2 || text[0] != text[len(text)-1] || (text[0] != '"' && text[0] != '\'') {
+		return text
+	}
+	if text[0] == '"' {
+		if unquoted, err := strconv.Unquote(text); err == nil {
+			return unquoted
+		}
+	}
+	return text[1 : len(text)-1]
+}
+
+func (m *XMLTreeModel) AddChild(child TreeModel) error {
+	if err := m.BaseTreeModel.AddChild(child); err != nil {
+		return err
+	}
+	m.adopt(child)
+	return nil
+}
+
+func (m *XMLTreeModel) AddChildAt(index int, child TreeModel) error {
+	if err := m.BaseTreeModel.AddChildAt(index, child); err != nil {
+		return err
+	}
+	m.adopt(child)
+	return nil
+}
+
+func (m *XMLTreeModel) RemoveChild() TreeModel {
+	removed := m.BaseTreeModel.RemoveChild()
+	m.release(removed)
+	return removed
+}
+
+func (m *XMLTreeModel) RemoveChildAt(index int) TreeModel {
+	removed := m.BaseTreeModel.RemoveChildAt(index)
+	m.release(removed)
+	return removed
+}
+
+// CanAccept accepts anything but documents in elements, and requires attribute names to be unique. Documents accept a
+// single element, along with comments, processing instructions and directives.
+func (m *XMLTreeModel) CanAccept(child TreeModel) bool {
+	xmlChild, ok := child.(*XMLTreeModel)
+	if !ok {
+		return false
+	}
+	childKind := xmlChild.Kind()
+	switch m.Kind() {
+	case XMLDocument:
+		switch childKind {
+		case XMLElement:
+			for _, sibling := range m.xmlChildren() {
+				if sibling != xmlChild && sibling.Kind() == XMLElement {
+					return false
+				}
+			}
+			return true
+		case XMLComment, XMLProcInst, XMLDirective:
+			return true
+		default:
+			return false
+		}
+	case XMLElement:
+		if childKind == XMLAttr {
+			return !m.hasAttr(xmlChild.Name(), xmlChild)
+		}
+		return childKind != XMLDocument
+	default:
+		return false
+	}
+}
+
+func (m *XMLTreeModel) MaxChildren() int {
+	if kind := m.Kind(); kind == XMLDocument || kind == XMLElement {
+		return Unlimited
+	}
+	return 0
+}
+
+func (m *XMLTreeModel) AllowedTypes() []TreeModel {
+	return []TreeModel{(*XMLTreeModel)(nil)}
+}
+
+func (m *XMLTreeModel) adopt(child TreeModel) {
+	if xmlChild, ok := child.(*XMLTreeModel); ok {
+		xmlChild.mux.Lock()
+		xmlChild.parent = m
+		xmlChild.mux.Unlock()
+	}
+}
+
+func (m *XMLTreeModel) release(child TreeModel) {
+	if xmlChild, ok := child.(*XMLTreeModel); ok && xmlChild != nil {
+		xmlChild.mux.Lock()
+		xmlChild.parent = nil
+		xmlChild.mux.Unlock()
+	}
+}
+
+func (m *XMLTreeModel) node() (XMLKind, string, string) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.kind, m.name, m.value
+}
+
+func (m *XMLTreeModel) getParent() *XMLTreeModel {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.parent
+}
+
+func (m *XMLTreeModel) xmlChildren() []*XMLTreeModel {
+	var children []*XMLTreeModel
+	for _, child := range m.Children() {
+		if xmlChild, ok := child.(*XMLTreeModel); ok {
+			children = append(children, xmlChild)
+		}
+	}
+	return children
+}
+
+// hasAttr reports whether an attribute other than except is called name.
+func (m *XMLTreeModel) hasAttr(name string, except *XMLTreeModel) bool {
+	for _, child := range m.xmlChildren() {
+		if childKind, childName, _ := child.node(); child != except && childKind == XMLAttr && childName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validXMLName checks that name can be written as an element or attribute name.
+func validXMLName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n<>&\"'=/!?") || strings.ContainsAny(name[:1], "-.0123456789") {
+		return errors.Wrapf(ErrInvalidXMLName, "'%s'", name)
+	}
+	return nil
+}
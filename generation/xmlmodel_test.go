@@ -0,0 +1,169 @@
+package generation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/theme"
+	testify "github.com/stretchr/testify/require"
+)
+
+const testXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- settings -->
+<app:config xmlns:app="urn:app" version="2">
+  <name>Tree &amp; view</name>
+  <app:item enabled="true"/>
+</app:config>
+`
+
+func TestReadXML(t *testing.T) {
+	assert := testify.New(t)
+	doc, err := ReadXML(strings.NewReader(testXML))
+	assert.NoError(err)
+	assert.Equal("#document", doc.DisplayString())
+	assert.Equal(theme.DocumentIcon(), doc.DisplayIcon())
+	assert.Equal([]string{`<?xml version="1.0" encoding="UTF-8"?>`, "<!-- settings -->", "<app:config>"}, displayStrings(doc.Children()))
+
+	config := doc.Children()[2].(*XMLTreeModel)
+	assert.Equal("element", config.DisplaySubtitle())
+	assert.Equal([]string{`xmlns:app="urn:app"`, `version="2"`, "<name>", "<app:item>"}, displayStrings(config.Children()), "Whitespace should be left out")
+	assert.Equal(theme.MoreHorizontalIcon(), config.Children()[0].DisplayIcon())
+	assert.Equal("attribute", config.Children()[0].(*XMLTreeModel).DisplaySubtitle())
+	text := config.Children()[2].Children()[0].(*XMLTreeModel)
+	assert.Equal(XMLText, text.Kind())
+	assert.Equal(`"Tree & view"`, text.DisplayString())
+
+	_, err = ReadXML(strings.NewReader("<a><b></a></b>"))
+	assert.Error(err)
+	_, err = ReadXML(strings.NewReader("<a>"))
+	assert.Error(err)
+	_, err = ReadXML(strings.NewReader("text<a/>"))
+	assert.Error(err)
+}
+
+func TestXMLTreeModel_Write(t *testing.T) {
+	assert := testify.New(t)
+	doc, err := ReadXML(strings.NewReader(testXML))
+	assert.NoError(err)
+	config := doc.Children()[2].(*XMLTreeModel)
+
+	assert.NoError(config.SetDisplayString("<app:settings>"))
+	assert.NoError(config.Children()[1].(*XMLTreeModel).SetDisplayString(`version="3"`))
+	assert.NoError(config.Children()[2].Children()[0].(*XMLTreeModel).SetDisplayString(`"Trees < forests"`))
+	assert.NoError(doc.Children()[1].(*XMLTreeModel).SetDisplayString("<!-- edited -->"))
+	attr, err := NewXMLAttr("id", `"main"`)
+	assert.NoError(err)
+	assert.NoError(config.Children()[3].AddChild(attr))
+
+	var buf bytes.Buffer
+	assert.NoError(doc.Write(&buf))
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<!-- edited -->
+<app:settings xmlns:app="urn:app" version="3">
+  <name>Trees &lt; forests</name>
+  <app:item enabled="true" id="&#34;main&#34;"/>
+</app:settings>
+`, buf.String())
+
+	reread, err := ReadXML(&buf)
+	assert.NoError(err)
+	assert.Equal(displayStrings(config.Children()), displayStrings(reread.Children()[2].Children()), "Written documents should read back the same")
+}
+
+func TestXMLTreeModel_RoundTrip(t *testing.T) {
+	assert := testify.New(t)
+	for _, input := range []string{
+		testXML,
+		"<p>Hello <b>world</b> <i>again</i>!</p>\n",
+		"<doc>\n  <p>Some <b>bold\n  <i>and</i> </b>\n    text</p>\n  <code><![CDATA[a < b && c]]></code>\n  <empty/>\n</doc>\n",
+	} {
+		doc, err := ReadXML(strings.NewReader(input))
+		assert.NoError(err)
+		var buf bytes.Buffer
+		assert.NoError(doc.Write(&buf))
+		assert.Equal(input, buf.String(), "Unedited documents should be written back out the same way")
+	}
+
+	doc, err := ReadXML(strings.NewReader("<p>Hello <b>world</b> <i>again</i>!</p>"))
+	assert.NoError(err)
+	p := doc.Children()[0]
+	assert.Equal([]string{`"Hello"`, "<b>", `""`, "<i>", `"!"`}, displayStrings(p.Children()), "Whitespace should be kept in mixed content")
+
+	doc, err = ReadXML(strings.NewReader("<a><![CDATA[x]]>y</a>"))
+	assert.NoError(err)
+	cdata := doc.Children()[0].Children()[0].(*XMLTreeModel)
+	assert.NoError(cdata.SetDisplayString("]]>"))
+	var buf bytes.Buffer
+	assert.NoError(doc.Write(&buf))
+	assert.Equal("<a><![CDATA[]]]]><![CDATA[>]]>y</a>\n", buf.String(), "CDATA should be split around its end marker")
+	reread, err := ReadXML(&buf)
+	assert.NoError(err)
+	var text string
+	for _, child := range reread.Children()[0].Children() {
+		text += child.(*XMLTreeModel).Value()
+	}
+	assert.Equal("]]>y", text)
+}
+
+func TestXMLTreeModel_SetDisplayString(t *testing.T) {
+	assert := testify.New(t)
+	doc, err := ReadXML(strings.NewReader(testXML))
+	assert.NoError(err)
+	config := doc.Children()[2].(*XMLTreeModel)
+	version := config.Children()[1].(*XMLTreeModel)
+
+	assert.ErrorIs(config.SetDisplayString("<two words>"), ErrInvalidXMLName)
+	assert.ErrorIs(config.SetDisplayString("1st"), ErrInvalidXMLName)
+	assert.NoError(version.SetDisplayString("release"))
+	assert.Equal(`release="2"`, version.DisplayString(), "The value should be kept if only the name is given")
+	assert.NoError(version.SetDisplayString("release='4'"))
+	assert.Equal("4", version.Value())
+	assert.ErrorIs(version.SetDisplayString(`xmlns:app="x"`), ErrDuplicateAttr)
+	assert.Error(doc.Children()[1].(*XMLTreeModel).SetDisplayString("a -- b"))
+	assert.ErrorIs(doc.SetDisplayString("doc"), ErrNotEditable)
+	assert.ErrorIs(doc.Children()[0].(*XMLTreeModel).SetDisplayString("xml"), ErrNotEditable)
+
+	_, err = NewXMLElement("")
+	assert.ErrorIs(err, ErrInvalidXMLName)
+	_, err = NewXMLComment("--")
+	assert.Error(err)
+}
+
+func TestXMLTreeModel_Registry(t *testing.T) {
+	assert := testify.New(t)
+	doc, err := ReadXML(strings.NewReader(testXML))
+	assert.NoError(err)
+	reg := NewTreeModelRegistry()
+	docID, err := reg.AddChild(ModelRoot, doc)
+	assert.NoError(err)
+	configID := reg.Children(docID)[2]
+	ids := reg.Children(configID)
+	versionID, nameID, itemID := ids[1], ids[2], ids[3]
+
+	element, err := NewXMLElement("other")
+	assert.NoError(err)
+	_, err = reg.AddChild(docID, element)
+	assert.Error(err, "Documents should only have one element")
+	_, err = reg.AddChild(docID, NewXMLText("text"))
+	assert.Error(err)
+	_, err = reg.AddChild(versionID, NewXMLText("text"))
+	assert.Error(err, "Attributes can't have children")
+	assert.Error(reg.MoveChild(nameID, docID, 0))
+	assert.NoError(reg.MoveChild(versionID, itemID, 0))
+	duplicate, err := NewXMLAttr("version", "1")
+	assert.NoError(err)
+	_, err = reg.AddChild(itemID, duplicate)
+	assert.Error(err, "Attribute names should be unique")
+	_, err = reg.AddChild(nameID, duplicate)
+	assert.NoError(err)
+	assert.Error(reg.MoveChild(configID, nameID, 0))
+
+	var buf bytes.Buffer
+	assert.NoError(doc.Children()[2].(*XMLTreeModel).Write(&buf))
+	assert.Equal(`<app:config xmlns:app="urn:app">
+  <name version="1">Tree &amp; view</name>
+  <app:item version="2" enabled="true"/>
+</app:config>
+`, buf.String())
+}
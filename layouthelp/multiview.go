@@ -82,3 +82,21 @@ func (v *MultiView) Replace(obj fyne.CanvasObject) {
 	v.pop()
 	v.push(obj)
 }
+
+// ReplaceTop replaces old with obj if old is the currently displayed fyne.CanvasObject, or pops old if obj is nil. It
+// returns false without changing anything if old isn't displayed, so a view can be replaced without removing one that
+// was pushed over it.
+func (v *MultiView) ReplaceTop(old, obj fyne.CanvasObject) bool {
+	defer v.container.Refresh()
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	stackDepth := len(v.viewStack)
+	if stackDepth == 0 || v.viewStack[stackDepth-1] != old {
+		return false
+	}
+	v.pop()
+	if obj != nil {
+		v.push(obj)
+	}
+	return true
+}
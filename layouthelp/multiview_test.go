@@ -111,3 +111,32 @@ func TestMultiView_Replace(t *testing.T) {
 	assert.Equal(obj3, mv.container.Objects[0])
 	assert.Equal(obj3, mv.viewStack[1])
 }
+
+func TestMultiView_ReplaceTop(t *testing.T) {
+	assert := testify.New(t)
+	mv := NewMultiView()
+
+	sz := fyne.NewSize(5, 5)
+	obj1 := testhelp.NewTestObject(sz)
+	obj2 := testhelp.NewTestObject(sz)
+	obj3 := testhelp.NewTestObject(sz)
+
+	a := test.NewApp()
+	w := a.NewWindow("")
+	w.SetContent(mv.Container())
+	w.ShowAndRun()
+
+	assert.False(mv.ReplaceTop(obj1, obj2), "Nothing is displayed")
+	mv.Push(obj1)
+	mv.Push(obj2)
+	assert.False(mv.ReplaceTop(obj1, obj3), "obj1 isn't displayed")
+	assert.Equal([]fyne.CanvasObject{obj1, obj2}, mv.viewStack)
+
+	assert.True(mv.ReplaceTop(obj2, obj3))
+	assert.Equal([]fyne.CanvasObject{obj1, obj3}, mv.viewStack)
+	assert.Equal(obj3, mv.container.Objects[0])
+
+	assert.True(mv.ReplaceTop(obj3, nil))
+	assert.Equal([]fyne.CanvasObject{obj1}, mv.viewStack)
+	assert.Equal(obj1, mv.container.Objects[0])
+}